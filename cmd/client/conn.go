@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Bytes read from and written to every connection the drivers open,
+// including HTTP headers, HTTP/2 frames, gRPC length prefixes and trailers.
+var (
+	connBytesRead    atomic.Int64
+	connBytesWritten atomic.Int64
+)
+
+// ConnStats is a snapshot of the connection byte counters.
+type ConnStats struct {
+	Read    int64
+	Written int64
+}
+
+func readConnStats() ConnStats {
+	return ConnStats{Read: connBytesRead.Load(), Written: connBytesWritten.Load()}
+}
+
+// Sub returns the bytes transferred between other and s.
+func (s ConnStats) Sub(other ConnStats) ConnStats {
+	return ConnStats{Read: s.Read - other.Read, Written: s.Written - other.Written}
+}
+
+var dialer = &net.Dialer{
+	Timeout:   30 * time.Second,
+	KeepAlive: 30 * time.Second,
+}
+
+// dialCounting dials like the default transports do and wraps the
+// connection so its traffic is counted.
+func dialCounting(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn}, nil
+}
+
+type countingConn struct {
+	net.Conn
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	connBytesRead.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	connBytesWritten.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+)
+
+// Result describes a single successful request made by a Driver.
+type Result struct {
+	// Bytes is the decoded payload size.
+	Bytes int
+	// WireBytes is the payload size as transferred, after compression. Zero
+	// means the payload wasn't compressed.
+	WireBytes int
+	// Records is the number of people decoded from the response.
+	Records int
+	// Messages is the number of response messages, zero for a single one.
+	Messages int
+	// FirstRecord is the time from the start of Do until the first record
+	// was decoded, zero if it only became available with the full response.
+	FirstRecord time.Duration
+	// Phases splits the latency of the request.
+	Phases Phases
+}
+
+// Driver implements one transport and protocol combination. Setup is called
+// once before any load is generated, Do performs exactly one request and
+// Teardown releases whatever Setup acquired. Do must be safe for concurrent use.
+type Driver interface {
+	Setup(ctx context.Context) error
+	Do(ctx context.Context) (Result, error)
+	Teardown() error
+}
+
+var drivers = make(map[string]func(config entity.Config) Driver)
+
+// registerDriver makes a driver available under the given protocol name.
+// Drivers register themselves from init so they stay self-contained.
+func registerDriver(protocol string, newDriver func(config entity.Config) Driver) {
+	if _, ok := drivers[protocol]; ok {
+		panic(fmt.Sprintf("driver %q registered twice", protocol))
+	}
+	drivers[protocol] = newDriver
+}
+
+func registeredProtocols() []string {
+	protocols := make([]string, 0, len(drivers))
+	for protocol := range drivers {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+	return protocols
+}
+
+// benchmarkProtocol sets up the driver for protocol, checks it with a single
+// test request and then runs the configured warmup and measured load.
+func benchmarkProtocol(protocol string, config entity.Config, run runKey) (*ClientAnalytics, error) {
+	newDriver, ok := drivers[protocol]
+	if !ok {
+		return nil, fmt.Errorf("unknown protocol %q, available: %v", protocol, registeredProtocols())
+	}
+	driver := newDriver(config)
+
+	// Handshakes are attributed to the protocol whose driver made them
+	drainHandshakes()
+
+	log.Printf("Setting up %s driver", protocol)
+	if err := driver.Setup(context.Background()); err != nil {
+		return nil, fmt.Errorf("setup %s: %w", protocol, err)
+	}
+	defer func() {
+		if err := driver.Teardown(); err != nil {
+			log.Printf("Failed to tear down %s driver: %v", protocol, err)
+		}
+	}()
+
+	// Test single request first
+	ctx, cancel := context.WithTimeout(context.Background(), config.RequestTimeout)
+	defer cancel()
+
+	log.Printf("Making test request")
+	result, err := driver.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("test request for %s failed: %w", protocol, err)
+	}
+	log.Printf("Test request successful, got %d people", result.Records)
+
+	return runBenchmark(protocol, config, run, func(analytics *ClientAnalytics, startTime time.Time) {
+		ctx, cancel := context.WithTimeout(context.Background(), config.RequestTimeout)
+		defer cancel()
+
+		doStart := time.Now()
+		result, err := driver.Do(ctx)
+		latency := time.Since(startTime)
+
+		if result.Messages == 0 {
+			result.Messages = 1
+		}
+		if result.WireBytes == 0 {
+			result.WireBytes = result.Bytes
+		}
+		if result.FirstRecord == 0 {
+			result.FirstRecord = latency
+		} else {
+			// Measure from the same origin as latency, which in open-loop
+			// mode is the intended rather than the actual send time
+			result.FirstRecord += doStart.Sub(startTime)
+		}
+		analytics.recordMetrics(latency, result, err == nil)
+	}), nil
+}
+
+// benchmarkProtocols runs every configured protocol in order.
+func benchmarkProtocols(config entity.Config, run runKey) ([]*ClientAnalytics, error) {
+	results := make([]*ClientAnalytics, 0, len(config.Protocols))
+	for _, protocol := range config.Protocols {
+		analytics, err := benchmarkProtocol(protocol, config, run)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, analytics)
+	}
+
+	compareWithUnary(results)
+	return results, nil
+}
+
+// unaryCounterparts maps streaming protocols to the buffered protocol they
+// are compared against.
+var unaryCounterparts = map[string]string{
+	ProtocolGrpcStream: ProtocolGrpc,
+	ProtocolRestStream: ProtocolRest,
+	ProtocolGrpcUpload: ProtocolGrpcCreate,
+	ProtocolGrpcSync:   ProtocolGrpcCreate,
+	ProtocolRestUpload: ProtocolRestCreate,
+}
+
+// compareWithUnary sets the per-message overhead of streaming results
+// relative to the buffered result of the same run, if there is one.
+func compareWithUnary(results []*ClientAnalytics) {
+	byProtocol := make(map[string]*ClientAnalytics, len(results))
+	for _, analytics := range results {
+		byProtocol[analytics.Protocol] = analytics
+	}
+
+	for _, analytics := range results {
+		unary, ok := byProtocol[unaryCounterparts[analytics.Protocol]]
+		if !ok || analytics.MessagesPerRequest <= 1 {
+			continue
+		}
+		overhead := float64(analytics.AverageLatency-unary.AverageLatency) / analytics.MessagesPerRequest
+		analytics.MessageOverhead = time.Duration(overhead)
+	}
+}
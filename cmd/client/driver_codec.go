@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+)
+
+// Every codec other than JSON, which is the plain rest driver, gets a
+// rest-<codec> driver requesting it from /benchmark through the Accept
+// header, e.g. rest-protobuf.
+func init() {
+	for _, name := range codec.Names() {
+		if name == codec.JSON {
+			continue
+		}
+		c, _ := codec.Lookup(name)
+		registerDriver(ProtocolRest+"-"+name, func(config entity.Config) Driver {
+			return &restCodecDriver{
+				restDriver: restDriver{config: config, url: populationURL(restBaseURL(config)+"/benchmark", config)},
+				codec:      c,
+			}
+		})
+	}
+}
+
+// restCodecDriver fetches the population in the codec's content type and
+// decodes it into the types the codec works on.
+type restCodecDriver struct {
+	restDriver
+	codec codec.Codec
+}
+
+func (d *restCodecDriver) Do(ctx context.Context) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Accept", d.codec.ContentType)
+
+	resp, trace, err := d.do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != d.codec.ContentType {
+		return Result{}, fmt.Errorf("server answered with %q instead of %q", contentType, d.codec.ContentType)
+	}
+
+	reader, wire, err := decodedBody(resp)
+	if err != nil {
+		return Result{}, err
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return Result{}, err
+	}
+	bodyRead := time.Now()
+
+	var records int
+	switch {
+	case d.codec.Proto:
+		var population pb.GetPopulationResponse
+		if err := d.codec.Decode(body, &population); err != nil {
+			return Result{}, err
+		}
+		records = len(population.Population)
+	case len(d.config.Fields) > 0:
+		// Projected people are encoded as maps, which not every codec can
+		// decode into structs
+		var population projectedPopulation
+		if err := d.codec.Decode(body, &population); err != nil {
+			return Result{}, err
+		}
+		records = len(population.Population)
+	default:
+		var population entity.GetPopulationResponse
+		if err := d.codec.Decode(body, &population); err != nil {
+			return Result{}, err
+		}
+		records = len(population.Population)
+	}
+
+	return Result{
+		Bytes:     len(body),
+		WireBytes: int(wire.n),
+		Records:   records,
+		Phases:    trace.phases(bodyRead, time.Since(bodyRead)),
+	}, nil
+}
+
+// projectedPopulation mirrors the server's response for a field projection.
+type projectedPopulation struct {
+	Population    []map[string]interface{} `json:"population"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+}
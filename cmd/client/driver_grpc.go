@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ProtocolGrpc       = "grpc"
+	ProtocolGrpcRaw    = "grpc-raw"
+	ProtocolGrpcStream = "grpc-stream"
+)
+
+const grpcTarget = "localhost:50051"
+
+func init() {
+	registerDriver(ProtocolGrpc, func(config entity.Config) Driver {
+		return &grpcDriver{
+			config: config,
+			dialOptions: []grpc.DialOption{
+				grpc.WithInitialWindowSize(1 << 23), // 8MB window size (up from 1MB)
+				grpc.WithInitialConnWindowSize(1 << 23),
+				grpc.WithKeepaliveParams(keepalive.ClientParameters{
+					Time:                30 * time.Second,
+					Timeout:             20 * time.Second,
+					PermitWithoutStream: true,
+				}),
+			},
+		}
+	})
+	registerDriver(ProtocolGrpcRaw, func(config entity.Config) Driver {
+		return &grpcRawDriver{grpcDriver{
+			config: config,
+			dialOptions: []grpc.DialOption{
+				grpc.WithInitialWindowSize(1 << 20),     // 1MB window size
+				grpc.WithInitialConnWindowSize(1 << 20), // 1MB connection window size
+				grpc.WithDefaultCallOptions(
+					grpc.MaxCallRecvMsgSize(1024 * 1024 * 10), // 10MB max message size
+				),
+			},
+		}}
+	})
+	registerDriver(ProtocolGrpcStream, func(config entity.Config) Driver {
+		return &grpcStreamDriver{grpcDriver{
+			config: config,
+			dialOptions: []grpc.DialOption{
+				grpc.WithInitialWindowSize(1 << 23),
+				grpc.WithInitialConnWindowSize(1 << 23),
+			},
+		}}
+	})
+}
+
+// grpcDriver fetches the population with the GetPopulation unary RPC.
+type grpcDriver struct {
+	config      entity.Config
+	dialOptions []grpc.DialOption
+	conn        *grpc.ClientConn
+	client      pb.PopulationServiceClient
+	request     *pb.GetPopulationRequest
+}
+
+func (d *grpcDriver) Setup(ctx context.Context) error {
+	tlsConfig, err := clientTLSConfig(d.config)
+	if err != nil {
+		return err
+	}
+	transportCredentials := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transportCredentials = timedCredentials{credentials.NewTLS(tlsConfig)}
+	}
+
+	options := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithStatsHandler(&callStatsHandler{}),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return dialCounting(ctx, "tcp", address)
+		}),
+	}, d.dialOptions...)
+	if d.config.Compression != "" {
+		if !compression.Supported(d.config.Compression) {
+			return fmt.Errorf("unsupported compression %q, available: %v", d.config.Compression, compression.Names())
+		}
+		options = append(options, grpc.WithDefaultCallOptions(grpc.UseCompressor(d.config.Compression)))
+	}
+
+	conn, err := grpc.NewClient(grpcTarget, options...)
+	if err != nil {
+		return err
+	}
+	d.conn = conn
+	d.client = pb.NewPopulationServiceClient(conn)
+	d.request = populationRequest(d.config)
+	return nil
+}
+
+func (d *grpcDriver) Do(ctx context.Context) (Result, error) {
+	ctx, call := withCallStats(ctx)
+	resp, err := d.client.GetPopulation(ctx, d.request, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Bytes:     proto.Size(resp),
+		WireBytes: call.received(),
+		Records:   len(resp.Population),
+		Phases:    call.phases(0),
+	}, nil
+}
+
+func (d *grpcDriver) Teardown() error {
+	return d.conn.Close()
+}
+
+// grpcRawDriver fetches pre-serialized population bytes with GetPopulationRaw
+// and unmarshals them on the client.
+type grpcRawDriver struct {
+	grpcDriver
+}
+
+func (d *grpcRawDriver) Do(ctx context.Context) (Result, error) {
+	ctx, call := withCallStats(ctx)
+	resp, err := d.client.GetPopulationRaw(ctx, d.request, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+
+	decodeStart := time.Now()
+	population := &pb.GetPopulationResponse{}
+	if err := proto.Unmarshal(resp.Data, population); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Bytes:     proto.Size(resp),
+		WireBytes: call.received(),
+		Records:   len(population.Population),
+		Phases:    call.phases(time.Since(decodeStart)),
+	}, nil
+}
+
+// grpcStreamDriver fetches the population with the StreamPopulation
+// server-streaming RPC, config.StreamBatchSize people per message.
+type grpcStreamDriver struct {
+	grpcDriver
+}
+
+func (d *grpcStreamDriver) Do(ctx context.Context) (Result, error) {
+	startTime := time.Now()
+
+	ctx, call := withCallStats(ctx)
+	stream, err := d.client.StreamPopulation(ctx, &pb.StreamPopulationRequest{
+		BatchSize: int32(d.config.StreamBatchSize),
+		Query:     d.request,
+	}, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		if result.FirstRecord == 0 && len(batch.Population) > 0 {
+			result.FirstRecord = time.Since(startTime)
+		}
+		result.Bytes += proto.Size(batch)
+		result.Records += len(batch.Population)
+		result.Messages++
+	}
+
+	result.WireBytes = call.received()
+	result.Phases = call.phases(0)
+	return result, nil
+}
+
+// callStats accumulates the compressed size of the messages of one call and
+// the times of its events, filled in by callStatsHandler, plus the time
+// spent unmarshalling responses.
+type callStats struct {
+	in  atomic.Int64
+	out atomic.Int64
+
+	mu        sync.Mutex
+	begin     time.Time
+	connReady time.Time
+	lastSent  time.Time
+	inHeader  time.Time
+	lastRecv  time.Time
+	decode    time.Duration
+}
+
+func (c *callStats) received() int {
+	return int(c.in.Load())
+}
+
+func (c *callStats) sent() int {
+	return int(c.out.Load())
+}
+
+// phases splits the call at its events. Like REST, Connect is the wait for
+// a usable connection: zero when the call began after its connection was
+// established. Messages are unmarshalled before they are reported as
+// received, so the codec's decode time is taken out of the transfer.
+// extraDecode is any decoding done after the call returned.
+func (c *callStats) phases(extraDecode time.Duration) Phases {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	connected := c.begin
+	if c.connReady.After(connected) {
+		connected = c.connReady
+	}
+	return Phases{
+		Connect:   between(c.begin, c.connReady),
+		Write:     between(connected, c.lastSent),
+		FirstByte: between(c.lastSent, c.inHeader),
+		Transfer:  max(between(c.inHeader, c.lastRecv)-c.decode, 0),
+		Decode:    c.decode + extraDecode,
+	}
+}
+
+// codec returns a call option that times response unmarshalling.
+func (c *callStats) codec() grpc.CallOption {
+	return grpc.ForceCodecV2(timedCodec{CodecV2: encoding.GetCodecV2(grpcproto.Name), call: c})
+}
+
+type timedCodec struct {
+	encoding.CodecV2
+	call *callStats
+}
+
+func (t timedCodec) Unmarshal(data mem.BufferSlice, v any) error {
+	start := time.Now()
+	err := t.CodecV2.Unmarshal(data, v)
+	elapsed := time.Since(start)
+
+	t.call.mu.Lock()
+	t.call.decode += elapsed
+	t.call.mu.Unlock()
+	return err
+}
+
+type callStatsKey struct{}
+
+func withCallStats(ctx context.Context) (context.Context, *callStats) {
+	call := &callStats{}
+	return context.WithValue(ctx, callStatsKey{}, call), call
+}
+
+// callStatsHandler records message sizes and event times into the callStats
+// attached to the call's context. It also tracks when the driver's current
+// connection was established, dialing and handshake included, as the
+// connection events carry no reference to the calls waiting on them.
+type callStatsHandler struct {
+	mu        sync.Mutex
+	connReady time.Time
+}
+
+func (*callStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *callStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	call, ok := ctx.Value(callStatsKey{}).(*callStats)
+	if !ok {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.InPayload:
+		call.in.Add(int64(s.CompressedLength))
+	case *stats.OutPayload:
+		call.out.Add(int64(s.CompressedLength))
+	}
+
+	// Headers go out on the connection the call was picked for, so it is
+	// the latest one by now
+	var connReady time.Time
+	if _, ok := s.(*stats.OutHeader); ok {
+		h.mu.Lock()
+		connReady = h.connReady
+		h.mu.Unlock()
+	}
+
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	switch s := s.(type) {
+	case *stats.Begin:
+		call.begin = s.BeginTime
+	case *stats.OutHeader:
+		call.connReady = connReady
+	case *stats.OutPayload:
+		call.lastSent = s.SentTime
+	case *stats.InHeader:
+		call.inHeader = time.Now()
+	case *stats.InPayload:
+		call.lastRecv = s.RecvTime
+	}
+}
+
+func (*callStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn notes when a connection is ready for calls. ConnBegin is
+// reported once the transport has dialed and finished the TLS handshake.
+func (h *callStatsHandler) HandleConn(_ context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnBegin); ok {
+		h.mu.Lock()
+		h.connReady = time.Now()
+		h.mu.Unlock()
+	}
+}
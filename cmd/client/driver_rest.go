@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"golang.org/x/net/http2"
+)
+
+// REST HTTP versions.
+const (
+	HTTPVersion1 = "1.1"
+	HTTPVersion2 = "2"
+)
+
+const (
+	ProtocolRest       = "rest"
+	ProtocolRestRaw    = "rest-raw"
+	ProtocolRestStream = "rest-stream"
+)
+
+func init() {
+	registerDriver(ProtocolRest, func(config entity.Config) Driver {
+		return &restDriver{config: config, url: populationURL(restBaseURL(config)+"/benchmark", config)}
+	})
+	// Served from cached JSON bytes, like grpc-raw is from cached protobuf
+	registerDriver(ProtocolRestRaw, func(config entity.Config) Driver {
+		return &restDriver{config: config, url: populationURL(restBaseURL(config)+"/benchmark/raw", config)}
+	})
+	registerDriver(ProtocolRestStream, func(config entity.Config) Driver {
+		values := populationValues(config)
+		values.Set("batch_size", strconv.Itoa(config.StreamBatchSize))
+		return &restStreamDriver{restDriver{
+			config: config,
+			url:    restBaseURL(config) + "/benchmark/stream?" + values.Encode(),
+		}}
+	})
+}
+
+// restDriver fetches the population as JSON over HTTP/1.1 or HTTP/2 and
+// decodes it.
+type restDriver struct {
+	config entity.Config
+	url    string
+	client *http.Client
+}
+
+func (d *restDriver) Setup(ctx context.Context) error {
+	if d.config.Compression != "" && !compression.Supported(d.config.Compression) {
+		return fmt.Errorf("unsupported compression %q, available: %v", d.config.Compression, compression.Names())
+	}
+	tlsConfig, err := clientTLSConfig(d.config)
+	if err != nil {
+		return err
+	}
+
+	transport, err := newRestTransport(d.config, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	d.client = &http.Client{
+		Transport: transport,
+		Timeout:   d.config.RequestTimeout,
+	}
+	return nil
+}
+
+// newRestTransport builds a transport for the configured HTTP version. Both
+// count their connections' traffic and time TLS handshakes.
+func newRestTransport(config entity.Config, tlsConfig *tls.Config) (http.RoundTripper, error) {
+	switch config.RestHTTPVersion {
+	case HTTPVersion1:
+		transport := &http.Transport{
+			DialContext:         dialCounting,
+			MaxIdleConns:        config.Concurrency,
+			MaxIdleConnsPerHost: config.Concurrency,
+			IdleConnTimeout:     90 * time.Second,
+			// Compression is negotiated and decoded by the driver so the
+			// compressed size and decompression time can be measured
+			DisableCompression: true,
+		}
+		if tlsConfig != nil {
+			transport.DialTLSContext = dialTLSCounting(tlsConfig)
+		}
+		return transport, nil
+
+	case HTTPVersion2:
+		transport := &http2.Transport{
+			DisableCompression: true,
+			IdleConnTimeout:    90 * time.Second,
+		}
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig
+			transport.DialTLSContext = func(ctx context.Context, network, address string, config *tls.Config) (net.Conn, error) {
+				// config is tlsConfig with h2 added to NextProtos
+				return dialTLSCounting(config)(ctx, network, address)
+			}
+		} else {
+			// h2c with prior knowledge: speak HTTP/2 over a plain connection
+			transport.AllowHTTP = true
+			transport.DialTLSContext = func(ctx context.Context, network, address string, _ *tls.Config) (net.Conn, error) {
+				return dialCounting(ctx, network, address)
+			}
+		}
+		return transport, nil
+	}
+
+	return nil, fmt.Errorf("unsupported REST HTTP version %q, use %q or %q", config.RestHTTPVersion, HTTPVersion1, HTTPVersion2)
+}
+
+// get requests the driver's URL.
+func (d *restDriver) get(ctx context.Context) (*http.Response, *requestTrace, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.do(req)
+}
+
+// do sends req asking for the configured compression, and checks the
+// response status and HTTP version. The trace times the request's phases.
+func (d *restDriver) do(req *http.Request) (*http.Response, *requestTrace, error) {
+	if d.config.Compression != "" {
+		req.Header.Set("Accept-Encoding", d.config.Compression)
+	}
+
+	req, trace := traceRequest(req)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.ProtoMajor == 1 && d.config.RestHTTPVersion == HTTPVersion2 {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("server answered with %s instead of HTTP/2", resp.Proto)
+	}
+	return resp, trace, nil
+}
+
+// decodedBody wraps a response body so it yields decompressed bytes. wire
+// counts the bytes received before decompression.
+func decodedBody(resp *http.Response) (body io.ReadCloser, wire *countingReader, err error) {
+	wire = &countingReader{r: resp.Body}
+	encoding := resp.Header.Get("Content-Encoding")
+	if encoding == "" {
+		return io.NopCloser(wire), wire, nil
+	}
+
+	body, err = compression.NewReader(encoding, wire)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, wire, nil
+}
+
+func (d *restDriver) Do(ctx context.Context) (Result, error) {
+	resp, trace, err := d.get(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	reader, wire, err := decodedBody(resp)
+	if err != nil {
+		return Result{}, err
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return Result{}, err
+	}
+	bodyRead := time.Now()
+
+	// Parse JSON but don't use the result
+	var population entity.GetPopulationResponse
+	if err := jsonCodec().Decode(body, &population); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Bytes:     len(body),
+		WireBytes: int(wire.n),
+		Records:   len(population.Population),
+		Phases:    trace.phases(bodyRead, time.Since(bodyRead)),
+	}, nil
+}
+
+// jsonCodec returns the JSON codec with the configured implementation.
+func jsonCodec() codec.Codec {
+	c, _ := codec.Lookup(codec.JSON)
+	return c
+}
+
+func (d *restDriver) Teardown() error {
+	d.client.CloseIdleConnections()
+	return nil
+}
+
+// restStreamDriver fetches the population as newline-delimited JSON and
+// decodes it one person at a time as the body arrives.
+type restStreamDriver struct {
+	restDriver
+}
+
+func (d *restStreamDriver) Do(ctx context.Context) (Result, error) {
+	startTime := time.Now()
+
+	resp, trace, err := d.get(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	reader, wire, err := decodedBody(resp)
+	if err != nil {
+		return Result{}, err
+	}
+	defer reader.Close()
+
+	var result Result
+	body := &countingReader{r: reader}
+	decoder := json.NewDecoder(body)
+	for {
+		var person entity.Person
+		if err := decoder.Decode(&person); err == io.EOF {
+			break
+		} else if err != nil {
+			return Result{}, err
+		}
+
+		if result.FirstRecord == 0 {
+			result.FirstRecord = time.Since(startTime)
+		}
+		result.Records++
+	}
+
+	result.Bytes = int(body.n)
+	result.WireBytes = int(wire.n)
+	// People are decoded as they arrive, so decoding is part of the transfer
+	result.Phases = trace.phases(time.Now(), 0)
+	// Chunk boundaries aren't visible to the client, so count lines
+	result.Messages = result.Records
+	return result, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
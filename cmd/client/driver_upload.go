@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ProtocolRestCreate = "rest-create"
+	ProtocolRestUpload = "rest-upload"
+	ProtocolGrpcCreate = "grpc-create"
+	ProtocolGrpcUpload = "grpc-upload"
+	ProtocolGrpcSync   = "grpc-sync"
+)
+
+func init() {
+	registerDriver(ProtocolRestCreate, func(config entity.Config) Driver {
+		return &restCreateDriver{restDriver: restDriver{config: config, url: restBaseURL(config) + "/population"}}
+	})
+	registerDriver(ProtocolRestUpload, func(config entity.Config) Driver {
+		return &restUploadDriver{restCreateDriver{restDriver: restDriver{config: config, url: restBaseURL(config) + "/population"}}}
+	})
+
+	uploadDialOptions := []grpc.DialOption{
+		grpc.WithInitialWindowSize(1 << 23),
+		grpc.WithInitialConnWindowSize(1 << 23),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(1024 * 1024 * 10), // 10MB max message size
+		),
+	}
+	registerDriver(ProtocolGrpcCreate, func(config entity.Config) Driver {
+		return &grpcCreateDriver{grpcDriver: grpcDriver{config: config, dialOptions: uploadDialOptions}}
+	})
+	registerDriver(ProtocolGrpcUpload, func(config entity.Config) Driver {
+		return &grpcUploadDriver{grpcCreateDriver{grpcDriver: grpcDriver{config: config, dialOptions: uploadDialOptions}}}
+	})
+	registerDriver(ProtocolGrpcSync, func(config entity.Config) Driver {
+		return &grpcSyncDriver{grpcCreateDriver{grpcDriver: grpcDriver{config: config, dialOptions: uploadDialOptions}}}
+	})
+}
+
+// loadUploadFixture reads the JSON fixture of the configured mock size as
+// the payload for upload drivers.
+func loadUploadFixture(size int) (*entity.GetPopulationResponse, error) {
+	data, err := os.ReadFile(testutil.FixturePath(size, "json"))
+	if err != nil {
+		return nil, err
+	}
+
+	population := &entity.GetPopulationResponse{}
+	if err := json.Unmarshal(data, population); err != nil {
+		return nil, err
+	}
+	return population, nil
+}
+
+// loadUploadFixturePB reads the protobuf fixture of the configured mock size
+// and splits it into batches of batchSize people.
+func loadUploadFixturePB(size, batchSize int) ([]*pb.CreatePopulationRequest, error) {
+	data, err := os.ReadFile(testutil.FixturePath(size, "pb"))
+	if err != nil {
+		return nil, err
+	}
+
+	population := &pb.GetPopulationResponse{}
+	if err := proto.Unmarshal(data, population); err != nil {
+		return nil, err
+	}
+
+	batchSize = max(batchSize, 1)
+	batches := make([]*pb.CreatePopulationRequest, 0, len(population.Population)/batchSize+1)
+	for start := 0; start < len(population.Population); start += batchSize {
+		end := min(start+batchSize, len(population.Population))
+		batches = append(batches, &pb.CreatePopulationRequest{Population: population.Population[start:end]})
+	}
+	return batches, nil
+}
+
+// restCreateDriver uploads the whole population as one JSON document with
+// POST /population, encoding it on every request.
+type restCreateDriver struct {
+	restDriver
+	population *entity.GetPopulationResponse
+}
+
+func (d *restCreateDriver) Setup(ctx context.Context) error {
+	population, err := loadUploadFixture(d.config.MockSize)
+	if err != nil {
+		return err
+	}
+	d.population = population
+	return d.restDriver.Setup(ctx)
+}
+
+func (d *restCreateDriver) Do(ctx context.Context) (Result, error) {
+	// Encoded with the configured JSON implementation, like responses
+	// are decoded
+	var encoded bytes.Buffer
+	if err := jsonCodec().Encode(&encoded, d.population); err != nil {
+		return Result{}, err
+	}
+	body := encoded.Bytes()
+
+	wire := body
+	if d.config.Compression != "" {
+		var buf bytes.Buffer
+		writer, err := compression.NewWriter(d.config.Compression, &buf)
+		if err != nil {
+			return Result{}, err
+		}
+		writer.Write(body)
+		if err := writer.Close(); err != nil {
+			return Result{}, err
+		}
+		wire = buf.Bytes()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(wire))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.config.Compression != "" {
+		req.Header.Set("Content-Encoding", d.config.Compression)
+	}
+
+	received, phases, err := d.send(req)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Bytes: len(body), WireBytes: len(wire), Records: received, Phases: phases}, nil
+}
+
+// send performs an upload request and returns the number of people the
+// server acknowledged.
+func (d *restCreateDriver) send(req *http.Request) (int, Phases, error) {
+	req, trace := traceRequest(req)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, Phases{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, Phases{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, Phases{}, err
+	}
+	bodyRead := time.Now()
+
+	var ack entity.CreatePopulationResponse
+	if err := jsonCodec().Decode(body, &ack); err != nil {
+		return 0, Phases{}, err
+	}
+	return ack.Received, trace.phases(bodyRead, time.Since(bodyRead)), nil
+}
+
+// restUploadDriver streams the population as newline-delimited JSON with
+// PUT /population and chunked transfer encoding.
+type restUploadDriver struct {
+	restCreateDriver
+}
+
+func (d *restUploadDriver) Do(ctx context.Context) (Result, error) {
+	reader, pipe := io.Pipe()
+	body := &countingReader{r: reader}
+	encoded := &countingWriter{w: pipe}
+
+	go func() {
+		var writer io.WriteCloser = nopWriteCloser{encoded}
+		if d.config.Compression != "" {
+			compressed, err := compression.NewWriter(d.config.Compression, pipe)
+			if err != nil {
+				pipe.CloseWithError(err)
+				return
+			}
+			writer = compressed
+			encoded.w = compressed
+		}
+
+		c := jsonCodec()
+		for i := range d.population.Population {
+			if err := c.Encode(encoded, &d.population.Population[i]); err != nil {
+				pipe.CloseWithError(err)
+				return
+			}
+		}
+		pipe.CloseWithError(writer.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, d.url, body)
+	if err != nil {
+		reader.Close()
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if d.config.Compression != "" {
+		req.Header.Set("Content-Encoding", d.config.Compression)
+	}
+
+	received, phases, err := d.send(req)
+	// Unblock the encoder if the request failed before the body was consumed
+	reader.Close()
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Bytes:     int(encoded.n),
+		WireBytes: int(body.n),
+		Records:   received,
+		Messages:  len(d.population.Population),
+		Phases:    phases,
+	}, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// grpcCreateDriver uploads the whole population with the CreatePopulation
+// unary RPC.
+type grpcCreateDriver struct {
+	grpcDriver
+	batches    []*pb.CreatePopulationRequest
+	population *pb.CreatePopulationRequest
+}
+
+func (d *grpcCreateDriver) Setup(ctx context.Context) error {
+	batches, err := loadUploadFixturePB(d.config.MockSize, d.config.StreamBatchSize)
+	if err != nil {
+		return err
+	}
+	d.batches = batches
+
+	d.population = &pb.CreatePopulationRequest{}
+	for _, batch := range batches {
+		d.population.Population = append(d.population.Population, batch.Population...)
+	}
+	return d.grpcDriver.Setup(ctx)
+}
+
+func (d *grpcCreateDriver) Do(ctx context.Context) (Result, error) {
+	ctx, call := withCallStats(ctx)
+	resp, err := d.client.CreatePopulation(ctx, d.population, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Bytes:     proto.Size(d.population),
+		WireBytes: call.sent(),
+		Records:   int(resp.Received),
+		Phases:    call.phases(0),
+	}, nil
+}
+
+// grpcUploadDriver uploads the population in batches with the
+// UploadPopulation client-streaming RPC.
+type grpcUploadDriver struct {
+	grpcCreateDriver
+}
+
+func (d *grpcUploadDriver) Do(ctx context.Context) (Result, error) {
+	ctx, call := withCallStats(ctx)
+	stream, err := d.client.UploadPopulation(ctx, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, batch := range d.batches {
+		if err := stream.Send(batch); err != nil {
+			return Result{}, err
+		}
+		result.Bytes += proto.Size(batch)
+		result.Messages++
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return Result{}, err
+	}
+	result.Records = int(resp.Received)
+	result.WireBytes = call.sent()
+	result.Phases = call.phases(0)
+	return result, nil
+}
+
+// grpcSyncDriver uploads the population in batches with the SyncPopulation
+// bidirectional RPC, reading acknowledgements while it sends.
+type grpcSyncDriver struct {
+	grpcCreateDriver
+}
+
+func (d *grpcSyncDriver) Do(ctx context.Context) (Result, error) {
+	startTime := time.Now()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx, call := withCallStats(ctx)
+
+	stream, err := d.client.SyncPopulation(ctx, call.codec())
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, batch := range d.batches {
+			if err := stream.Send(batch); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	for {
+		ack, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		if result.FirstRecord == 0 {
+			result.FirstRecord = time.Since(startTime)
+		}
+		result.Records += int(ack.Received)
+		result.Messages++
+	}
+	if err := <-sendErr; err != nil {
+		return Result{}, err
+	}
+
+	for _, batch := range d.batches {
+		result.Bytes += proto.Size(batch)
+	}
+	result.WireBytes = call.sent()
+	result.Phases = call.phases(0)
+	return result, nil
+}
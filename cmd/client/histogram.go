@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Samples are recorded in nanoseconds from 1ns up to the configured maximum.
+// With a smaller maximum than this they are clamped to values that the
+// reports, in hundredths of a millisecond, round to zero.
+const minHistogramMaxLatency = time.Millisecond
+
+// Percentiles reported for every latency histogram.
+var reportedPercentiles = []float64{50, 90, 95, 99, 99.9, 99.99}
+
+type LatencyPercentiles struct {
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	P999  time.Duration `json:"p99_9"`
+	P9999 time.Duration `json:"p99_99"`
+}
+
+type HistogramBucket struct {
+	From  time.Duration `json:"from"`
+	To    time.Duration `json:"to"`
+	Count int64         `json:"count"`
+}
+
+// LatencyHistogram records every latency sample in a fixed-size HDR histogram,
+// so memory stays bounded no matter how many requests are made.
+type LatencyHistogram struct {
+	hist *hdrhistogram.Histogram
+}
+
+func newLatencyHistogram(maxLatency time.Duration, significantFigures int) *LatencyHistogram {
+	return &LatencyHistogram{
+		hist: hdrhistogram.New(1, int64(maxLatency), significantFigures),
+	}
+}
+
+func (h *LatencyHistogram) Record(latency time.Duration) {
+	// Clamp samples outside the trackable range instead of dropping them
+	value := int64(latency)
+	if value < 1 {
+		value = 1
+	}
+	if highest := h.hist.HighestTrackableValue(); value > highest {
+		value = highest
+	}
+	h.hist.RecordValue(value)
+}
+
+func (h *LatencyHistogram) Percentiles() LatencyPercentiles {
+	values := h.hist.ValueAtPercentiles(reportedPercentiles)
+	return LatencyPercentiles{
+		P50:   time.Duration(values[50]),
+		P90:   time.Duration(values[90]),
+		P95:   time.Duration(values[95]),
+		P99:   time.Duration(values[99]),
+		P999:  time.Duration(values[99.9]),
+		P9999: time.Duration(values[99.99]),
+	}
+}
+
+// Buckets returns the non-empty buckets of the histogram in ascending order.
+func (h *LatencyHistogram) Buckets() []HistogramBucket {
+	buckets := make([]HistogramBucket, 0)
+	for _, bar := range h.hist.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		buckets = append(buckets, HistogramBucket{
+			From:  time.Duration(bar.From),
+			To:    time.Duration(bar.To),
+			Count: bar.Count,
+		})
+	}
+	return buckets
+}
+
+func (h *LatencyHistogram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SignificantFigures int64             `json:"significant_figures"`
+		HighestTrackable   time.Duration     `json:"highest_trackable"`
+		TotalCount         int64             `json:"total_count"`
+		Buckets            []HistogramBucket `json:"buckets"`
+	}{
+		SignificantFigures: h.hist.SignificantFigures(),
+		HighestTrackable:   time.Duration(h.hist.HighestTrackableValue()),
+		TotalCount:         h.hist.TotalCount(),
+		Buckets:            h.Buckets(),
+	})
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/resources"
+)
+
+const (
+	LoadModeClosed = "closed"
+	LoadModeOpen   = "open"
+)
+
+// Requests dispatched later than this after their intended send time are
+// reported as late in open-loop mode.
+const lateDispatchThreshold = time.Millisecond
+
+// Requests per run when neither TOTAL_REQUESTS nor DURATION is set. With
+// only DURATION set the number of requests is unlimited.
+const defaultTotalRequests = 10000
+
+// The client heap is checked this often for its peak, whatever the resource
+// sample interval, so a buffered response's allocation isn't missed.
+const heapPeakInterval = 10 * time.Millisecond
+
+// runBenchmark runs the optional warmup phase followed by the measured phase
+// for a single protocol. Warmup samples are kept in a separate analytics
+// entry so they don't pollute the headline numbers.
+func runBenchmark(protocol string, config entity.Config, run runKey, do func(analytics *ClientAnalytics, startTime time.Time)) *ClientAnalytics {
+	var warmup *ClientAnalytics
+	if config.WarmupRequests > 0 || config.WarmupDuration > 0 {
+		warmupConfig := config
+		warmupConfig.TotalRequests = config.WarmupRequests
+		warmupConfig.Duration = config.WarmupDuration
+
+		warmup = newClientAnalytics(protocol, warmupConfig)
+		log.Printf("Warming up %s", protocol)
+		runLoad(warmupConfig, warmup, func(startTime time.Time) {
+			do(warmup, startTime)
+		})
+		warmup.summarize()
+	}
+
+	// Profiling starts before the first scrape and stops after the second,
+	// so its admin requests stay out of the server metrics
+	var profiles *profileCapture
+	if config.Profile {
+		var err error
+		if profiles, err = startProfiling(protocol, config, run); err != nil {
+			log.Printf("Failed to start profiling, leaving profiles out: %v", err)
+		}
+	}
+	var serverBefore *serverMetricsSnapshot
+	if config.ServerMetrics {
+		var err error
+		if serverBefore, err = scrapeServerMetrics(config); err != nil {
+			log.Printf("Failed to scrape server metrics, leaving them out: %v", err)
+		}
+	}
+
+	// Start from a clean heap so the peak isn't inflated by earlier runs
+	runtime.GC()
+	sampler := resources.StartSampler(config.ResourceSampleInterval, 0)
+	heapPeak := resources.StartHeapPeak(heapPeakInterval)
+	measureStart := time.Now()
+	compressionStats := compression.ReadStats()
+	connStats := readConnStats()
+
+	analytics := newClientAnalytics(protocol, config)
+	analytics.Warmup = warmup
+	runLoad(config, analytics, func(startTime time.Time) {
+		do(analytics, startTime)
+	})
+	measureEnd := time.Now()
+	analytics.Resources = &ResourceUsage{Client: resources.Summarize(sampler.Stop(), analytics.TotalRequests)}
+	analytics.PeakHeapBytes = max(heapPeak.Stop(), analytics.Resources.Client.PeakHeapBytes)
+	analytics.recordConnStats(readConnStats().Sub(connStats))
+	analytics.recordHandshakes(drainHandshakes())
+
+	// Everything the server is asked for from here on is outside the
+	// measured window
+	if serverBefore != nil {
+		if serverAfter, err := scrapeServerMetrics(config); err != nil {
+			log.Printf("Failed to scrape server metrics, leaving them out: %v", err)
+		} else {
+			analytics.ServerMetrics = serverMetricsDelta(serverBefore, serverAfter)
+			serverCompression := serverCompressionDelta(serverBefore, serverAfter)
+			analytics.ServerCompressTime = serverCompression.CompressTime
+			analytics.ServerDecompressTime = serverCompression.DecompressTime
+		}
+	}
+	if serverSamples, err := fetchServerResources(config, measureStart, measureEnd); err != nil {
+		log.Printf("Failed to fetch server resource usage, leaving it out: %v", err)
+	} else {
+		analytics.Resources.Server = resources.Summarize(serverSamples, analytics.TotalRequests)
+	}
+	if profiles != nil {
+		names, err := profiles.stop()
+		if err != nil {
+			log.Printf("Failed to write profiles: %v", err)
+		}
+		analytics.Profiles = names
+	}
+	compressionStats = compression.ReadStats().Sub(compressionStats)
+	analytics.CompressTime = compressionStats.CompressTime
+	analytics.DecompressTime = compressionStats.DecompressTime
+	analytics.summarize()
+	printAnalytics(analytics)
+	return analytics
+}
+
+// runLoad drives do according to the configured load mode. do receives the
+// time the request was meant to start, which latency is measured from.
+func runLoad(config entity.Config, analytics *ClientAnalytics, do func(startTime time.Time)) {
+	if config.LoadMode == LoadModeOpen {
+		log.Printf("Starting %s benchmark in open-loop mode at %.2f requests/sec, %s", analytics.Protocol, config.TargetRPS, describeLimits(config))
+		runOpenLoop(analytics, config.TotalRequests, config.Duration, config.TargetRPS, do)
+		return
+	}
+
+	log.Printf("Starting %s benchmark with %d concurrent clients, %s", analytics.Protocol, config.Concurrency, describeLimits(config))
+	runClosedLoop(config.Concurrency, config.TotalRequests, config.Duration, do)
+}
+
+func describeLimits(config entity.Config) string {
+	switch {
+	case config.TotalRequests > 0 && config.Duration > 0:
+		return fmt.Sprintf("up to %d requests or %s", config.TotalRequests, config.Duration)
+	case config.Duration > 0:
+		return fmt.Sprintf("for %s", config.Duration)
+	default:
+		return fmt.Sprintf("%d requests", config.TotalRequests)
+	}
+}
+
+// runClosedLoop starts concurrency clients that make back-to-back requests
+// until total requests have been issued or duration has elapsed.
+func runClosedLoop(concurrency, total int, duration time.Duration, do func(startTime time.Time)) {
+	var wg sync.WaitGroup
+	var issued atomic.Int64
+	deadline := time.Now().Add(duration)
+
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func(clientID int) {
+			defer wg.Done()
+			for {
+				if total > 0 && issued.Add(1) > int64(total) {
+					return
+				}
+				if duration > 0 && time.Now().After(deadline) {
+					return
+				}
+				do(time.Now())
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// runOpenLoop dispatches requests on a fixed schedule of rps requests per
+// second without waiting for earlier requests to complete, so slow responses
+// can't throttle the offered load (coordinated omission).
+func runOpenLoop(analytics *ClientAnalytics, total int, duration time.Duration, rps float64, do func(startTime time.Time)) {
+	var wg sync.WaitGroup
+	interval := time.Duration(float64(time.Second) / rps)
+	start := time.Now()
+
+	for i := 0; total <= 0 || i < total; i++ {
+		intended := start.Add(time.Duration(i) * interval)
+		if duration > 0 && intended.Sub(start) >= duration {
+			break
+		}
+		if wait := time.Until(intended); wait > 0 {
+			time.Sleep(wait)
+		}
+		analytics.recordDispatch(time.Since(intended))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do(intended)
+		}()
+	}
+
+	wg.Wait()
+}
@@ -1,64 +1,169 @@
 package main
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
 	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
-	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/keepalive"
-	"google.golang.org/protobuf/proto"
-)
-
-const (
-	ProtocolRest    = "rest"
-	ProtocolGrpc    = "grpc"
-	ProtocolGrpcRaw = "grpc-raw"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/resources"
 )
 
 type ClientAnalytics struct {
-	Protocol        string        `json:"protocol"`
-	TotalRequests   int64         `json:"total_requests"`
-	SuccessRequests int64         `json:"success_requests"`
-	FailedRequests  int64         `json:"failed_requests"`
-	AverageLatency  time.Duration `json:"average_latency"`
-	MinLatency      time.Duration `json:"min_latency"`
-	MaxLatency      time.Duration `json:"max_latency"`
-	TotalLatency    time.Duration `json:"total_latency"`
-	TotalBytes      int64         `json:"total_bytes"`
-	AverageBodySize float64       `json:"average_body_size"`
-	StartTime       time.Time     `json:"start_time"`
-	EndTime         time.Time     `json:"end_time"`
-	TotalDuration   time.Duration `json:"total_duration"`
-	RequestsPerSec  float64       `json:"requests_per_sec"`
-	BytesPerSec     float64       `json:"bytes_per_sec"`
-	MockSize        int           `json:"mock_size"`
-	mu              sync.RWMutex
+	Protocol         string             `json:"protocol"`
+	TotalRequests    int64              `json:"total_requests"`
+	SuccessRequests  int64              `json:"success_requests"`
+	FailedRequests   int64              `json:"failed_requests"`
+	AverageLatency   time.Duration      `json:"average_latency"`
+	MinLatency       time.Duration      `json:"min_latency"`
+	MaxLatency       time.Duration      `json:"max_latency"`
+	TotalLatency     time.Duration      `json:"total_latency"`
+	TotalBytes       int64              `json:"total_bytes"`
+	AverageBodySize  float64            `json:"average_body_size"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	TotalDuration    time.Duration      `json:"total_duration"`
+	RequestsPerSec   float64            `json:"requests_per_sec"`
+	BytesPerSec      float64            `json:"bytes_per_sec"`
+	MockSize         int                `json:"mock_size"`
+	HTTPVersion      string             `json:"http_version,omitempty"`
+	JSONEncoder      string             `json:"json_encoder,omitempty"`
+	Concurrency      int                `json:"concurrency"`
+	Fields           []string           `json:"fields,omitempty"`
+	Percentiles      LatencyPercentiles `json:"percentiles"`
+	Histogram        *LatencyHistogram  `json:"histogram"`
+	LoadMode         string             `json:"load_mode"`
+	TargetRPS        float64            `json:"target_rps,omitempty"`
+	LateRequests     int64              `json:"late_requests"`
+	MaxDispatchDelay time.Duration      `json:"max_dispatch_delay"`
+	Warmup           *ClientAnalytics   `json:"warmup,omitempty"`
+
+	// Time until the first record of a response was decoded. For buffered
+	// protocols this is the full request latency.
+	AverageFirstRecord     time.Duration      `json:"average_first_record"`
+	FirstRecordPercentiles LatencyPercentiles `json:"first_record_percentiles"`
+	FirstRecordHistogram   *LatencyHistogram  `json:"first_record_histogram"`
+	totalFirstRecord       time.Duration
+
+	TotalMessages      int64   `json:"total_messages"`
+	MessagesPerRequest float64 `json:"messages_per_request"`
+	// MessageOverhead is the extra latency per message compared to the
+	// buffered counterpart, only set for streaming protocols.
+	MessageOverhead time.Duration `json:"message_overhead,omitempty"`
+
+	// Phases breaks successful requests' latency down, see Phases.
+	Phases map[string]*PhaseLatency `json:"phases"`
+
+	// ServerMetrics is what the server recorded during the measured phase.
+	ServerMetrics *ServerMetrics `json:"server_metrics,omitempty"`
+
+	// PeakHeapBytes is the highest live client heap seen during the run,
+	// checked every heapPeakInterval and by the Resources samples.
+	PeakHeapBytes uint64 `json:"peak_heap_bytes"`
+
+	// Resources holds the sampled CPU and memory usage of client and server.
+	Resources *ResourceUsage `json:"resources,omitempty"`
+
+	// Profiles lists the pprof files written to the output directory for
+	// this run, when profiling is on.
+	Profiles []string `json:"profiles,omitempty"`
+
+	// Bytes on the wire after compression, and the time the client spent
+	// compressing and decompressing bodies.
+	Compression      string        `json:"compression,omitempty"`
+	TotalWireBytes   int64         `json:"total_wire_bytes"`
+	AverageWireSize  float64       `json:"average_wire_size"`
+	CompressionRatio float64       `json:"compression_ratio"`
+	CompressTime     time.Duration `json:"compress_time"`
+	DecompressTime   time.Duration `json:"decompress_time"`
+	// The server's side of the same, from its metrics when SERVER_METRICS
+	// is on
+	ServerCompressTime   time.Duration `json:"server_compress_time,omitempty"`
+	ServerDecompressTime time.Duration `json:"server_decompress_time,omitempty"`
+
+	// Bytes actually read and written on the client's connections. The
+	// framing overhead is everything beyond the payload: HTTP headers,
+	// HTTP/2 frames, gRPC length prefixes, trailers and the request itself.
+	ConnBytesRead    int64   `json:"conn_bytes_read"`
+	ConnBytesWritten int64   `json:"conn_bytes_written"`
+	AverageConnBytes float64 `json:"average_conn_bytes"`
+	FramingOverhead  float64 `json:"framing_overhead"`
+
+	// TLS handshakes made by the protocol's connections, from setup through
+	// the measured phase. Requests that opened a connection include the
+	// handshake in their latency too.
+	TLSMode              string             `json:"tls_mode,omitempty"`
+	Handshakes           int                `json:"handshakes"`
+	AverageHandshake     time.Duration      `json:"average_handshake"`
+	HandshakePercentiles LatencyPercentiles `json:"handshake_percentiles"`
+	handshakeHistogram   *LatencyHistogram
+
+	mu sync.RWMutex
+}
+
+func newClientAnalytics(protocol string, config entity.Config) *ClientAnalytics {
+	analytics := &ClientAnalytics{
+		Protocol:    protocol,
+		MinLatency:  time.Hour,
+		StartTime:   time.Now(),
+		MockSize:    config.MockSize,
+		Concurrency: config.Concurrency,
+		Fields:      config.Fields,
+		Histogram:   newLatencyHistogram(config.HistogramMaxLatency, config.HistogramSignificantFigures),
+		LoadMode:    config.LoadMode,
+		Compression: config.Compression,
+		TLSMode:     config.TLSMode,
+
+		FirstRecordHistogram: newLatencyHistogram(config.HistogramMaxLatency, config.HistogramSignificantFigures),
+		handshakeHistogram:   newLatencyHistogram(config.HistogramMaxLatency, config.HistogramSignificantFigures),
+		Phases:               make(map[string]*PhaseLatency, len(phaseNames)),
+	}
+	for _, name := range phaseNames {
+		analytics.Phases[name] = &PhaseLatency{
+			Histogram: newLatencyHistogram(config.HistogramMaxLatency, config.HistogramSignificantFigures),
+		}
+	}
+	if config.LoadMode == LoadModeOpen {
+		analytics.TargetRPS = config.TargetRPS
+	}
+	if strings.HasPrefix(protocol, ProtocolRest) {
+		analytics.HTTPVersion = config.RestHTTPVersion
+		analytics.JSONEncoder = config.JSONEncoder
+	}
+	return analytics
 }
 
-func (a *ClientAnalytics) recordMetrics(latency time.Duration, bodySize int, success bool) {
+func (a *ClientAnalytics) recordMetrics(latency time.Duration, result Result, success bool) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	a.TotalRequests++
 	if success {
 		a.SuccessRequests++
+		a.totalFirstRecord += result.FirstRecord
+		a.FirstRecordHistogram.Record(result.FirstRecord)
+		a.AverageFirstRecord = time.Duration(int64(a.totalFirstRecord) / a.SuccessRequests)
+		a.TotalMessages += int64(result.Messages)
+		a.MessagesPerRequest = float64(a.TotalMessages) / float64(a.SuccessRequests)
+		for i, d := range result.Phases.durations() {
+			if d > 0 {
+				a.Phases[phaseNames[i]].record(d)
+			}
+		}
 	} else {
 		a.FailedRequests++
 	}
 
 	a.TotalLatency += latency
-	a.TotalBytes += int64(bodySize)
+	a.TotalBytes += int64(result.Bytes)
+	a.TotalWireBytes += int64(result.WireBytes)
+	a.Histogram.Record(latency)
 
 	if latency < a.MinLatency || a.MinLatency == 0 {
 		a.MinLatency = latency
@@ -69,6 +174,10 @@ func (a *ClientAnalytics) recordMetrics(latency time.Duration, bodySize int, suc
 
 	a.AverageLatency = time.Duration(int64(a.TotalLatency) / a.TotalRequests)
 	a.AverageBodySize = float64(a.TotalBytes) / float64(a.TotalRequests)
+	a.AverageWireSize = float64(a.TotalWireBytes) / float64(a.TotalRequests)
+	if a.TotalWireBytes > 0 {
+		a.CompressionRatio = float64(a.TotalBytes) / float64(a.TotalWireBytes)
+	}
 
 	a.EndTime = time.Now()
 	a.TotalDuration = a.EndTime.Sub(a.StartTime)
@@ -76,203 +185,123 @@ func (a *ClientAnalytics) recordMetrics(latency time.Duration, bodySize int, suc
 	a.BytesPerSec = float64(a.TotalBytes) / a.TotalDuration.Seconds()
 }
 
-var restClient = &http.Client{
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 100,
-		IdleConnTimeout:     90 * time.Second,
-	},
-	Timeout: 10 * time.Second,
-}
+// recordDispatch tracks how far behind schedule an open-loop request was sent.
+func (a *ClientAnalytics) recordDispatch(delay time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-func main() {
-	config := entity.Config{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Failed to parse environment variables: %v", err)
+	if delay > lateDispatchThreshold {
+		a.LateRequests++
 	}
-
-	analytics := make([]*ClientAnalytics, 0)
-	restAnalytics := benchmarkRest(config.MockSize)
-	grpcAnalytics := benchmarkGrpc(config.MockSize)
-	grpcRawAnalytics := benchmarkGrpcRaw(config.MockSize)
-
-	analytics = append(analytics, restAnalytics, grpcAnalytics, grpcRawAnalytics)
-
-	// to json file
-	jsonData, err := json.MarshalIndent(analytics, "", "  ")
-	if err != nil {
-		log.Fatalf("Failed to marshal analytics to JSON: %v", err)
+	if delay > a.MaxDispatchDelay {
+		a.MaxDispatchDelay = delay
 	}
-
-	os.WriteFile(config.OutputDir+"/"+config.OutputFile, jsonData, 0644)
 }
 
-func benchmarkRest(size int) *ClientAnalytics {
-	analytics := &ClientAnalytics{
-		Protocol:   ProtocolRest,
-		MinLatency: time.Hour,
-		StartTime:  time.Now(),
-		MockSize:   size,
-	}
-
-	// Number of concurrent requests
-	concurrency := 100
-	// Number of requests per goroutine
-	requestsPerClient := 100
-
-	var wg sync.WaitGroup
-	wg.Add(concurrency)
-
-	log.Printf("Starting REST benchmark with %d concurrent clients, %d requests each", concurrency, requestsPerClient)
+// recordConnStats attributes the connection traffic of the measured phase
+// to its requests.
+func (a *ClientAnalytics) recordConnStats(stats ConnStats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	for i := 0; i < concurrency; i++ {
-		go func(clientID int) {
-			defer wg.Done()
-			for j := 0; j < requestsPerClient; j++ {
-				makeRestRequest(analytics)
-			}
-		}(i)
+	a.ConnBytesRead = stats.Read
+	a.ConnBytesWritten = stats.Written
+	if a.TotalRequests > 0 {
+		a.AverageConnBytes = float64(stats.Read+stats.Written) / float64(a.TotalRequests)
+		a.FramingOverhead = a.AverageConnBytes - a.AverageWireSize
 	}
-
-	wg.Wait()
-	printAnalytics(analytics)
-	return analytics
 }
 
-func benchmarkGrpc(size int) *ClientAnalytics {
-	log.Printf("Starting gRPC benchmark")
-
-	// Create gRPC connection with better options
-	conn, err := grpc.Dial("localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithInitialWindowSize(1<<23), // 8MB window size (up from 1MB)
-		grpc.WithInitialConnWindowSize(1<<23),
-		grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                30 * time.Second,
-			Timeout:             20 * time.Second,
-			PermitWithoutStream: true,
-		}),
-	)
-	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
-	}
-	defer conn.Close()
-
-	// Create client
-	client := pb.NewPopulationServiceClient(conn)
-	log.Printf("Created gRPC client")
-
-	// Test single request first
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+// recordHandshakes adds TLS handshake durations to the analytics.
+func (a *ClientAnalytics) recordHandshakes(durations []time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	log.Printf("Making test request")
-	resp, err := client.GetPopulation(ctx, &pb.GetPopulationRequest{})
-	if err != nil {
-		log.Fatalf("Test request failed: %v", err)
+	var total time.Duration
+	for _, d := range durations {
+		a.handshakeHistogram.Record(d)
+		total += d
 	}
-	log.Printf("Test request successful, got %d people", len(resp.Population))
-
-	// Continue with benchmark...
-	analytics := &ClientAnalytics{
-		Protocol:   ProtocolGrpc,
-		MinLatency: time.Hour,
-		StartTime:  time.Now(),
-		MockSize:   size,
+	a.Handshakes += len(durations)
+	if len(durations) > 0 {
+		a.AverageHandshake = total / time.Duration(len(durations))
 	}
+}
 
-	// Number of concurrent requests
-	concurrency := 100
-	// Number of requests per goroutine
-	requestsPerClient := 100
-
-	var wg sync.WaitGroup
-	wg.Add(concurrency)
-
-	log.Printf("Starting gRPC benchmark with %d concurrent clients, %d requests each", concurrency, requestsPerClient)
+// summarize computes the percentile summary once all samples are recorded.
+func (a *ClientAnalytics) summarize() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	for i := 0; i < concurrency; i++ {
-		go func(clientID int) {
-			defer wg.Done()
-			for j := 0; j < requestsPerClient; j++ {
-				makeGrpcRequest(client, analytics)
-			}
-		}(i)
+	a.Percentiles = a.Histogram.Percentiles()
+	a.FirstRecordPercentiles = a.FirstRecordHistogram.Percentiles()
+	a.HandshakePercentiles = a.handshakeHistogram.Percentiles()
+	for _, phase := range a.Phases {
+		phase.Percentiles = phase.Histogram.Percentiles()
 	}
-
-	wg.Wait()
-	printAnalytics(analytics)
-	return analytics
 }
 
-func benchmarkGrpcRaw(size int) *ClientAnalytics {
-	log.Printf("Starting gRPC benchmark")
-
-	// Create gRPC connection with better options
-	conn, err := grpc.Dial("localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithInitialWindowSize(1<<20),     // 1MB window size
-		grpc.WithInitialConnWindowSize(1<<20), // 1MB connection window size
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(1024*1024*10), // 10MB max message size
-		),
-	)
-	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+func main() {
+	config := entity.Config{}
+	if err := env.Parse(&config); err != nil {
+		log.Fatalf("Failed to parse environment variables: %v", err)
 	}
-	defer conn.Close()
-
-	// Create client
-	client := pb.NewPopulationServiceClient(conn)
-	log.Printf("Created gRPC client")
-
-	// Test single request first
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	log.Printf("Making test request")
-	resp, err := client.GetPopulationRaw(ctx, &pb.GetPopulationRequest{})
-	if err != nil {
-		log.Fatalf("Test request failed: %v", err)
+	if err := codec.UseJSON(config.JSONEncoder); err != nil {
+		log.Fatalf("Failed to select JSON encoder: %v", err)
 	}
-
-	population := &pb.GetPopulationResponse{}
-	if err := proto.Unmarshal(resp.Data, population); err != nil {
-		log.Fatalf("Test request failed: %v", err)
+	if config.TotalRequests < 0 || config.Duration < 0 {
+		log.Fatalf("TOTAL_REQUESTS and DURATION must not be negative")
 	}
-
-	log.Printf("Test request successful, got %d people", len(population.Population))
-
-	// Continue with benchmark...
-	analytics := &ClientAnalytics{
-		Protocol:   ProtocolGrpcRaw,
-		MinLatency: time.Hour,
-		StartTime:  time.Now(),
-		MockSize:   size,
+	if config.TotalRequests == 0 && config.Duration == 0 {
+		config.TotalRequests = defaultTotalRequests
+	}
+	log.Printf("Each run is %s", describeLimits(config))
+	if config.Concurrency <= 0 {
+		log.Fatalf("CONCURRENCY must be positive")
+	}
+	if config.LoadMode == LoadModeOpen && config.TargetRPS <= 0 {
+		log.Fatalf("TARGET_RPS must be positive in open-loop mode")
+	}
+	if config.ResourceSampleInterval <= 0 {
+		log.Fatalf("RESOURCE_SAMPLE_INTERVAL must be positive")
+	}
+	if config.HistogramMaxLatency < minHistogramMaxLatency {
+		log.Fatalf("HISTOGRAM_MAX_LATENCY must be at least %s", minHistogramMaxLatency)
+	}
+	if config.HistogramSignificantFigures < 1 || config.HistogramSignificantFigures > 5 {
+		log.Fatalf("HISTOGRAM_SIGNIFICANT_FIGURES must be between 1 and 5")
 	}
 
-	// Number of concurrent requests
-	concurrency := 100
-	// Number of requests per goroutine
-	requestsPerClient := 100
+	if config.ScenarioFile != "" {
+		scenario, err := loadScenario(config.ScenarioFile, config)
+		if err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+		report, err := runScenario(scenario, config)
+		if err != nil {
+			log.Fatalf("Failed to run scenario: %v", err)
+		}
+		writeOutput(config, report)
+		return
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(concurrency)
+	analytics, err := benchmarkProtocols(config, runKey{Repetition: 1})
+	if err != nil {
+		log.Fatalf("Failed to run benchmark: %v", err)
+	}
 
-	log.Printf("Starting gRPC benchmark with %d concurrent clients, %d requests each", concurrency, requestsPerClient)
+	writeOutput(config, analytics)
+}
 
-	for i := 0; i < concurrency; i++ {
-		go func(clientID int) {
-			defer wg.Done()
-			for j := 0; j < requestsPerClient; j++ {
-				makeGrpcRequestRaw(client, analytics)
-			}
-		}(i)
+func writeOutput(config entity.Config, v any) {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal analytics to JSON: %v", err)
 	}
 
-	wg.Wait()
-	printAnalytics(analytics)
-	return analytics
+	if err := os.WriteFile(config.OutputDir+"/"+config.OutputFile, jsonData, 0644); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
 }
 
 func printAnalytics(a *ClientAnalytics) {
@@ -282,73 +311,83 @@ func printAnalytics(a *ClientAnalytics) {
 	fmt.Printf("\nBenchmark Results:\n")
 	fmt.Printf("================\n")
 	fmt.Printf("Protocol:           %s\n", a.Protocol)
+	if a.HTTPVersion != "" {
+		fmt.Printf("HTTP Version:       %s\n", a.HTTPVersion)
+		fmt.Printf("JSON Encoder:       %s\n", a.JSONEncoder)
+	}
 	fmt.Printf("Total Requests:     %d\n", a.TotalRequests)
 	fmt.Printf("Success Requests:   %d\n", a.SuccessRequests)
 	fmt.Printf("Failed Requests:    %d\n", a.FailedRequests)
 	fmt.Printf("Average Latency:    %.2fms\n", float64(a.AverageLatency.Microseconds())/1000)
 	fmt.Printf("Min Latency:        %.2fms\n", float64(a.MinLatency.Microseconds())/1000)
 	fmt.Printf("Max Latency:        %.2fms\n", float64(a.MaxLatency.Microseconds())/1000)
+	fmt.Printf("P50 Latency:        %.2fms\n", float64(a.Percentiles.P50.Microseconds())/1000)
+	fmt.Printf("P90 Latency:        %.2fms\n", float64(a.Percentiles.P90.Microseconds())/1000)
+	fmt.Printf("P95 Latency:        %.2fms\n", float64(a.Percentiles.P95.Microseconds())/1000)
+	fmt.Printf("P99 Latency:        %.2fms\n", float64(a.Percentiles.P99.Microseconds())/1000)
+	fmt.Printf("P99.9 Latency:      %.2fms\n", float64(a.Percentiles.P999.Microseconds())/1000)
+	fmt.Printf("P99.99 Latency:     %.2fms\n", float64(a.Percentiles.P9999.Microseconds())/1000)
+	for _, name := range phaseNames {
+		if phase := a.Phases[name]; phase.Count > 0 {
+			fmt.Printf("  %-17s %.2fms avg, %.2fms p50, %.2fms p99\n", name+":", float64(phase.Average.Microseconds())/1000, float64(phase.Percentiles.P50.Microseconds())/1000, float64(phase.Percentiles.P99.Microseconds())/1000)
+		}
+	}
+	if a.LoadMode == LoadModeOpen {
+		fmt.Printf("Target Rate:        %.2f requests/sec\n", a.TargetRPS)
+		fmt.Printf("Late Requests:      %d\n", a.LateRequests)
+		fmt.Printf("Max Dispatch Delay: %.2fms\n", float64(a.MaxDispatchDelay.Microseconds())/1000)
+	}
+	fmt.Printf("First Record:       %.2fms avg, %.2fms p99\n", float64(a.AverageFirstRecord.Microseconds())/1000, float64(a.FirstRecordPercentiles.P99.Microseconds())/1000)
+	if a.MessagesPerRequest > 1 {
+		fmt.Printf("Messages/Request:   %.2f\n", a.MessagesPerRequest)
+	}
+	fmt.Printf("Peak Client Heap:   %.2f MB\n", float64(a.PeakHeapBytes)/1024/1024)
 	fmt.Printf("Total Duration:     %.2fs\n", a.TotalDuration.Seconds())
 	fmt.Printf("Requests/sec:       %.2f\n", a.RequestsPerSec)
 	fmt.Printf("Average Body Size:  %.2f bytes\n", a.AverageBodySize)
 	fmt.Printf("Transfer Rate:      %.2f MB/sec\n", a.BytesPerSec/1024/1024)
-}
-
-func makeRestRequest(analytics *ClientAnalytics) {
-	startTime := time.Now()
-
-	resp, err := restClient.Get("http://localhost:8080/benchmark")
-	if err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
+	fmt.Printf("On the Wire:        %.2f bytes/request (%.2f read, %.2f written)\n", a.AverageConnBytes, float64(a.ConnBytesRead)/float64(max(a.TotalRequests, 1)), float64(a.ConnBytesWritten)/float64(max(a.TotalRequests, 1)))
+	fmt.Printf("Framing Overhead:   %.2f bytes/request\n", a.FramingOverhead)
+	if a.TLSMode != "" {
+		fmt.Printf("TLS Handshakes:     %d (%s), %.2fms avg, %.2fms p99\n", a.Handshakes, a.TLSMode, float64(a.AverageHandshake.Microseconds())/1000, float64(a.HandshakePercentiles.P99.Microseconds())/1000)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
+	if a.Compression != "" {
+		fmt.Printf("Compression:        %s, %.2f bytes on the wire (%.2fx)\n", a.Compression, a.AverageWireSize, a.CompressionRatio)
+		fmt.Printf("Compress Time:      %.2fms\n", float64(a.CompressTime.Microseconds())/1000)
+		fmt.Printf("Decompress Time:    %.2fms\n", float64(a.DecompressTime.Microseconds())/1000)
+		if a.ServerMetrics != nil {
+			fmt.Printf("Server Compress:    %.2fms\n", float64(a.ServerCompressTime.Microseconds())/1000)
+			fmt.Printf("Server Decompress:  %.2fms\n", float64(a.ServerDecompressTime.Microseconds())/1000)
+		}
 	}
+	if a.ServerMetrics != nil {
+		for _, e := range a.ServerMetrics.Endpoints {
+			fmt.Printf("Server Endpoint:    %s %s, %d requests, %.2fms avg, %.2f MB written\n", e.Transport, e.Endpoint, e.Requests, float64(e.AverageDuration.Microseconds())/1000, float64(e.BytesWritten)/1024/1024)
+		}
+		for _, s := range a.ServerMetrics.Serialization {
+			label := "Server Encoding:   "
+			if s.Op == "decode" {
+				label = "Server Decoding:   "
+			}
+			fmt.Printf("%s %s %s, %.2fms avg\n", label, s.Transport, s.Codec, float64(s.Average.Microseconds())/1000)
+		}
 
-	// Parse JSON but don't use the result
-	var population entity.GetPopulationResponse
-	if err := json.Unmarshal(body, &population); err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
 	}
-
-	latency := time.Since(startTime)
-	analytics.recordMetrics(latency, len(body), resp.StatusCode == http.StatusOK)
-}
-
-func makeGrpcRequest(client pb.PopulationServiceClient, analytics *ClientAnalytics) {
-	startTime := time.Now()
-
-	resp, err := client.GetPopulation(context.Background(), &pb.GetPopulationRequest{})
-	if err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
+	if a.Resources != nil {
+		printUsage("Client", a.Resources.Client)
+		if a.Resources.Server != nil {
+			printUsage("Server", a.Resources.Server)
+		}
 	}
-
-	latency := time.Since(startTime)
-	analytics.recordMetrics(latency, proto.Size(resp), true)
-}
-
-func makeGrpcRequestRaw(client pb.PopulationServiceClient, analytics *ClientAnalytics) {
-	startTime := time.Now()
-
-	resp, err := client.GetPopulationRaw(context.Background(), &pb.GetPopulationRequest{})
-	if err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
+	if len(a.Profiles) > 0 {
+		fmt.Printf("Profiles:           %d written\n", len(a.Profiles))
 	}
-
-	population := &pb.GetPopulationResponse{}
-	if err := proto.Unmarshal(resp.Data, population); err != nil {
-		analytics.recordMetrics(time.Since(startTime), 0, false)
-		return
+	if a.Warmup != nil {
+		fmt.Printf("Warmup Requests:    %d (excluded, %.2fs, p99 %.2fms)\n", a.Warmup.TotalRequests, a.Warmup.TotalDuration.Seconds(), float64(a.Warmup.Percentiles.P99.Microseconds())/1000)
 	}
+}
 
-	latency := time.Since(startTime)
-	analytics.recordMetrics(latency, proto.Size(resp), true)
+func printUsage(side string, u *resources.Usage) {
+	fmt.Printf("%-20s%.2fms total, %.2fµs/request, %.2f KB/request allocated\n", side+" CPU:", float64(u.CPUTime.Microseconds())/1000, float64(u.CPUPerRequest.Nanoseconds())/1000, u.AllocBytesPerRequest/1024)
+	fmt.Printf("%-20s%.2f MB peak RSS, %d GC cycles, %.2fms GC pauses, %d peak goroutines\n", side+" Memory:", float64(u.PeakRSSBytes)/1024/1024, u.GCCycles, float64(u.GCPauseTotal.Microseconds())/1000, u.PeakGoroutines)
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Request phases, in the order they happen.
+const (
+	PhaseConnect   = "connect"
+	PhaseWrite     = "write"
+	PhaseFirstByte = "first_byte"
+	PhaseTransfer  = "transfer"
+	PhaseDecode    = "decode"
+)
+
+var phaseNames = []string{PhaseConnect, PhaseWrite, PhaseFirstByte, PhaseTransfer, PhaseDecode}
+
+// Phases splits a request's latency. Connect is the wait for a usable
+// connection, dialing and handshaking if a new one is needed. Write ends when
+// the request is sent, FirstByte when the response starts arriving, Transfer
+// when the body has been read, and Decode is the time spent unmarshalling.
+// A zero phase wasn't measured, e.g. decoding interleaved with the transfer.
+type Phases struct {
+	Connect   time.Duration
+	Write     time.Duration
+	FirstByte time.Duration
+	Transfer  time.Duration
+	Decode    time.Duration
+}
+
+func (p Phases) durations() []time.Duration {
+	return []time.Duration{p.Connect, p.Write, p.FirstByte, p.Transfer, p.Decode}
+}
+
+// between returns the time from start to end, or zero if either is missing
+// or they overlap.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// PhaseLatency is the latency distribution of one request phase.
+type PhaseLatency struct {
+	Count       int64              `json:"count"`
+	Average     time.Duration      `json:"average"`
+	Percentiles LatencyPercentiles `json:"percentiles"`
+	Histogram   *LatencyHistogram  `json:"histogram"`
+	total       time.Duration
+}
+
+func (p *PhaseLatency) record(d time.Duration) {
+	p.Count++
+	p.total += d
+	p.Average = p.total / time.Duration(p.Count)
+	p.Histogram.Record(d)
+}
+
+// requestTrace collects the httptrace events of one REST request.
+type requestTrace struct {
+	mu           sync.Mutex
+	getConn      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// traceRequest returns req with a trace recording the phase boundaries.
+func traceRequest(req *http.Request) (*http.Request, *requestTrace) {
+	t := &requestTrace{}
+	mark := func(at *time.Time) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if at.IsZero() {
+			*at = time.Now()
+		}
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn:              func(string) { mark(&t.getConn) },
+		GotConn:              func(httptrace.GotConnInfo) { mark(&t.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { mark(&t.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+// phases splits the request at the traced events. bodyRead is when the
+// response body was fully read.
+func (t *requestTrace) phases(bodyRead time.Time, decode time.Duration) Phases {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return Phases{
+		Connect:   between(t.getConn, t.gotConn),
+		Write:     between(t.gotConn, t.wroteRequest),
+		FirstByte: between(t.wroteRequest, t.firstByte),
+		Transfer:  between(t.firstByte, bodyRead),
+		Decode:    decode,
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import "os/exec"
+
+// Windows has no process groups to signal, so only cmd itself is managed.
+// A server started through a wrapper like `go run` may outlive it.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// stopProcessGroup kills cmd, there's no graceful SIGTERM on Windows.
+func stopProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/profiling"
+)
+
+// runKey tells apart the runs of a scenario that share a mock size and
+// concurrency. Single runs are the first repetition of field set 0.
+type runKey struct {
+	FieldSet   int
+	Repetition int
+}
+
+// profileCapture profiles client and server for the duration of one run.
+type profileCapture struct {
+	config   entity.Config
+	protocol string
+	run      runKey
+	server   bool
+}
+
+// startProfiling starts profiling the client and asks the server to do the
+// same. A server that can't be profiled is left out.
+func startProfiling(protocol string, config entity.Config, run runKey) (*profileCapture, error) {
+	if err := profiling.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &profileCapture{config: config, protocol: protocol, run: run}
+	if err := p.serverRequest("start", nil); err != nil {
+		log.Printf("Failed to start server profiling, leaving it out: %v", err)
+	} else {
+		p.server = true
+	}
+	return p, nil
+}
+
+// stop ends profiling and writes the profiles next to the output file,
+// returning their names.
+func (p *profileCapture) stop() ([]string, error) {
+	profiles := map[string]map[string][]byte{}
+	if p.server {
+		var server map[string][]byte
+		if err := p.serverRequest("stop", &server); err != nil {
+			log.Printf("Failed to collect server profiles, leaving them out: %v", err)
+		} else {
+			profiles["server"] = server
+		}
+	}
+
+	client, err := profiling.Stop()
+	if err != nil {
+		return nil, err
+	}
+	profiles["client"] = client
+
+	if err := os.MkdirAll(p.config.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+	var names []string
+	for side, byKind := range profiles {
+		for kind, data := range byKind {
+			// Keyed by protocol, mock size, concurrency, field set and
+			// repetition, e.g. rest_1000_c100_f0_r1_server_cpu.pprof
+			name := fmt.Sprintf("%s_%d_c%d_f%d_r%d_%s_%s.pprof", p.protocol, p.config.MockSize,
+				p.config.Concurrency, p.run.FieldSet, p.run.Repetition, side, kind)
+			if err := os.WriteFile(filepath.Join(p.config.OutputDir, name), data, 0644); err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// serverRequest posts to the server's profiling endpoint for action and
+// decodes the response into v, if given.
+func (p *profileCapture) serverRequest(action string, v any) error {
+	client, err := adminClient(p.config)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(restBaseURL(p.config)+"/admin/profile/"+action, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
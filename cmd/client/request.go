@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// populationRequest builds the gRPC request for the configured filters.
+func populationRequest(config entity.Config) *pb.GetPopulationRequest {
+	req := &pb.GetPopulationRequest{
+		Role:     config.FilterRole,
+		Active:   config.FilterActive,
+		State:    config.FilterState,
+		City:     config.FilterCity,
+		PageSize: int32(config.PageSize),
+	}
+	if len(config.Fields) > 0 {
+		req.FieldMask = &fieldmaskpb.FieldMask{Paths: config.Fields}
+	}
+	return req
+}
+
+// populationURL appends the configured filters to a REST endpoint as query
+// parameters matching GetPopulationRequest.
+func populationURL(endpoint string, config entity.Config) string {
+	values := populationValues(config)
+	if len(values) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + values.Encode()
+}
+
+// populationValues returns the configured filters as query parameters.
+func populationValues(config entity.Config) url.Values {
+	values := url.Values{}
+	if config.FilterRole != "" {
+		values.Set("role", config.FilterRole)
+	}
+	if config.FilterActive != nil {
+		values.Set("active", strconv.FormatBool(*config.FilterActive))
+	}
+	if config.FilterState != "" {
+		values.Set("state", config.FilterState)
+	}
+	if config.FilterCity != "" {
+		values.Set("city", config.FilterCity)
+	}
+	if config.PageSize > 0 {
+		values.Set("page_size", strconv.Itoa(config.PageSize))
+	}
+	if len(config.Fields) > 0 {
+		values.Set("fields", strings.Join(config.Fields, ","))
+	}
+	return values
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/resources"
+)
+
+// ResourceUsage is the CPU and memory cost of a measured run on both sides.
+// Per-request figures are divided by the run's total requests.
+type ResourceUsage struct {
+	Client *resources.Usage `json:"client"`
+	Server *resources.Usage `json:"server,omitempty"`
+}
+
+// fetchServerResources reads the server's resource samples covering since to
+// until. Like the client's own series, it starts with the last sample before
+// since and ends with the first one after until.
+func fetchServerResources(config entity.Config, since, until time.Time) ([]resources.Sample, error) {
+	client, err := adminClient(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(restBaseURL(config) + "/admin/resources?since=" + url.QueryEscape(since.Format(time.RFC3339Nano)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var samples []resources.Sample
+	if err := json.NewDecoder(resp.Body).Decode(&samples); err != nil {
+		return nil, err
+	}
+	for i, sample := range samples {
+		if !sample.Time.Before(until) {
+			return samples[:i+1], nil
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+)
+
+// How long to wait for the server to come up with the requested dataset.
+const serverReadyTimeout = 2 * time.Minute
+
+type ScenarioRun struct {
+	MockSize    int                `json:"mock_size"`
+	Concurrency int                `json:"concurrency"`
+	Fields      []string           `json:"fields,omitempty"`
+	Repetition  int                `json:"repetition"`
+	Results     []*ClientAnalytics `json:"results"`
+}
+
+type ScenarioReport struct {
+	Scenario  entity.Scenario `json:"scenario"`
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	Runs      []*ScenarioRun  `json:"runs"`
+}
+
+func loadScenario(path string, config entity.Config) (entity.Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity.Scenario{}, err
+	}
+
+	var scenario entity.Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return entity.Scenario{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if len(scenario.MockSizes) == 0 {
+		scenario.MockSizes = []int{config.MockSize}
+	}
+	if len(scenario.Protocols) == 0 {
+		scenario.Protocols = config.Protocols
+	}
+	if len(scenario.Concurrency) == 0 {
+		scenario.Concurrency = []int{config.Concurrency}
+	}
+	for _, concurrency := range scenario.Concurrency {
+		if concurrency <= 0 {
+			return entity.Scenario{}, fmt.Errorf("parse %s: concurrency must be positive, got %d", path, concurrency)
+		}
+	}
+	if len(scenario.FieldSets) == 0 {
+		scenario.FieldSets = [][]string{config.Fields}
+	}
+	if scenario.Repetitions <= 0 {
+		scenario.Repetitions = 1
+	}
+	return scenario, nil
+}
+
+// runScenario executes the whole matrix and returns one consolidated report.
+func runScenario(scenario entity.Scenario, config entity.Config) (*ScenarioReport, error) {
+	report := &ScenarioReport{
+		Scenario:  scenario,
+		StartTime: time.Now(),
+		Runs:      make([]*ScenarioRun, 0),
+	}
+
+	for _, size := range scenario.MockSizes {
+		stop, err := prepareServer(scenario, config, size)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, concurrency := range scenario.Concurrency {
+			for fieldSet, fields := range scenario.FieldSets {
+				for repetition := 1; repetition <= scenario.Repetitions; repetition++ {
+					log.Printf("Scenario %q: mock size %d, concurrency %d, fields %v, repetition %d/%d",
+						scenario.Name, size, concurrency, fields, repetition, scenario.Repetitions)
+
+					runConfig := config
+					runConfig.MockSize = size
+					runConfig.Concurrency = concurrency
+					runConfig.Fields = fields
+					runConfig.Protocols = scenario.Protocols
+
+					results, err := benchmarkProtocols(runConfig, runKey{FieldSet: fieldSet, Repetition: repetition})
+					if err != nil {
+						stop()
+						return nil, err
+					}
+					report.Runs = append(report.Runs, &ScenarioRun{
+						MockSize:    size,
+						Concurrency: concurrency,
+						Fields:      fields,
+						Repetition:  repetition,
+						Results:     results,
+					})
+				}
+			}
+		}
+
+		stop()
+	}
+
+	report.EndTime = time.Now()
+	return report, nil
+}
+
+// prepareServer makes sure the server is serving a dataset of the given size,
+// either by starting it from the scenario's server command or by switching
+// the running server's dataset through the admin API. The returned function
+// stops anything that was started.
+func prepareServer(scenario entity.Scenario, config entity.Config, size int) (func(), error) {
+	stop := func() {}
+
+	if len(scenario.ServerCommand) > 0 {
+		log.Printf("Starting server with MOCK_SIZE=%d", size)
+		cmd := exec.Command(scenario.ServerCommand[0], scenario.ServerCommand[1:]...)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("MOCK_SIZE=%d", size))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		setProcessGroup(cmd)
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("start server: %w", err)
+		}
+
+		stop = func() {
+			stopProcessGroup(cmd)
+			cmd.Wait()
+		}
+	} else {
+		log.Printf("Switching server dataset to mock size %d", size)
+		if err := setServerDataset(config, size); err != nil {
+			return nil, fmt.Errorf("switch server dataset: %w", err)
+		}
+	}
+
+	if err := waitForDataset(config, size); err != nil {
+		stop()
+		return nil, err
+	}
+	return stop, nil
+}
+
+func setServerDataset(config entity.Config, size int) error {
+	body, err := json.Marshal(map[string]int{"size": size})
+	if err != nil {
+		return err
+	}
+
+	client, err := adminClient(config)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(restBaseURL(config)+"/admin/dataset", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// waitForDataset polls the REST endpoint until the server answers with a
+// population of the expected size.
+func waitForDataset(config entity.Config, size int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), serverReadyTimeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		count, err := fetchDatasetSize(ctx, config)
+		if err == nil && count == size {
+			return nil
+		}
+		if err != nil {
+			// A freshly started server regenerates its certificates
+			resetAdminClient()
+		}
+		if err == nil {
+			err = fmt.Errorf("server is serving %d people, want %d", count, size)
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server not ready with mock size %d: %w", size, lastErr)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func fetchDatasetSize(ctx context.Context, config entity.Config) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, restBaseURL(config)+"/benchmark", nil)
+	if err != nil {
+		return 0, err
+	}
+	client, err := adminClient(config)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var population entity.GetPopulationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&population); err != nil {
+		return 0, err
+	}
+	return len(population.Population), nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Server metric families pulled into the results.
+const (
+	metricRequests      = "benchmark_server_requests_total"
+	metricHandler       = "benchmark_server_handler_duration_seconds"
+	metricSerialization = "benchmark_server_serialization_duration_seconds"
+	metricBytesWritten  = "benchmark_server_bytes_written_total"
+	metricCompression   = "benchmark_server_compression_seconds_total"
+)
+
+// ServerMetrics is what the server recorded while a protocol was measured,
+// taken from the difference of two scrapes of its /metrics endpoint.
+type ServerMetrics struct {
+	Endpoints     []ServerEndpointMetrics      `json:"endpoints"`
+	Serialization []ServerSerializationMetrics `json:"serialization"`
+}
+
+type ServerEndpointMetrics struct {
+	Transport       string        `json:"transport"`
+	Endpoint        string        `json:"endpoint"`
+	Requests        int64         `json:"requests"`
+	Errors          int64         `json:"errors"`
+	AverageDuration time.Duration `json:"average_duration"`
+	BytesWritten    int64         `json:"bytes_written"`
+}
+
+// ServerSerializationMetrics is the time the server spent encoding
+// responses or, for Op "decode", decoding requests.
+type ServerSerializationMetrics struct {
+	Transport string        `json:"transport"`
+	Codec     string        `json:"codec"`
+	Op        string        `json:"op"`
+	Count     int64         `json:"count"`
+	Average   time.Duration `json:"average"`
+}
+
+// serverMetricsSnapshot maps metric family and label set to a value. For
+// histograms the value is the sum and the count is kept apart.
+type serverMetricsSnapshot struct {
+	values map[string]float64
+	counts map[string]uint64
+}
+
+// scrapeServerMetrics reads the server's /metrics endpoint.
+func scrapeServerMetrics(config entity.Config) (*serverMetricsSnapshot, error) {
+	client, err := adminClient(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(restBaseURL(config) + "/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &serverMetricsSnapshot{values: make(map[string]float64), counts: make(map[string]uint64)}
+	for _, name := range []string{metricRequests, metricHandler, metricSerialization, metricBytesWritten, metricCompression} {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			key := metricKey(name, metric.GetLabel())
+			switch {
+			case metric.GetCounter() != nil:
+				snapshot.values[key] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				snapshot.values[key] = metric.GetHistogram().GetSampleSum()
+				snapshot.counts[key] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// metricKey is the family name followed by the label values in name order,
+// separated by tabs.
+func metricKey(name string, labels []*dto.LabelPair) string {
+	sorted := append([]*dto.LabelPair(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GetName() < sorted[j].GetName() })
+
+	parts := []string{name}
+	for _, label := range sorted {
+		parts = append(parts, label.GetValue())
+	}
+	return strings.Join(parts, "\t")
+}
+
+// serverMetricsDelta summarizes what changed between two scrapes.
+func serverMetricsDelta(before, after *serverMetricsSnapshot) *ServerMetrics {
+	delta := func(key string) float64 { return after.values[key] - before.values[key] }
+	countDelta := func(key string) int64 { return int64(after.counts[key] - before.counts[key]) }
+
+	endpoints := make(map[[2]string]*ServerEndpointMetrics)
+	endpoint := func(transport, name string) *ServerEndpointMetrics {
+		k := [2]string{transport, name}
+		if endpoints[k] == nil {
+			endpoints[k] = &ServerEndpointMetrics{Transport: transport, Endpoint: name}
+		}
+		return endpoints[k]
+	}
+
+	result := &ServerMetrics{}
+	for key := range after.values {
+		parts := strings.Split(key, "\t")
+		switch parts[0] {
+		case metricRequests:
+			// Labels sorted by name: code, endpoint, transport
+			if requests := int64(delta(key)); requests > 0 {
+				e := endpoint(parts[3], parts[2])
+				e.Requests += requests
+				if parts[1] != "200" && parts[1] != "OK" {
+					e.Errors += requests
+				}
+			}
+		case metricHandler:
+			// endpoint, transport
+			if count := countDelta(key); count > 0 {
+				endpoint(parts[2], parts[1]).AverageDuration = seconds(delta(key) / float64(count))
+			}
+		case metricBytesWritten:
+			if written := int64(delta(key)); written > 0 {
+				endpoint(parts[2], parts[1]).BytesWritten = written
+			}
+		case metricSerialization:
+			// codec, op, transport
+			if count := countDelta(key); count > 0 {
+				result.Serialization = append(result.Serialization, ServerSerializationMetrics{
+					Transport: parts[3],
+					Codec:     parts[1],
+					Op:        parts[2],
+					Count:     count,
+					Average:   seconds(delta(key) / float64(count)),
+				})
+			}
+		}
+	}
+
+	for _, e := range endpoints {
+		// Scrapes and admin requests around the run aren't part of it
+		if e.Requests > 0 && e.Endpoint != "/metrics" && !strings.HasPrefix(e.Endpoint, "/admin/") {
+			result.Endpoints = append(result.Endpoints, *e)
+		}
+	}
+	sort.Slice(result.Endpoints, func(i, j int) bool { return result.Endpoints[i].Endpoint < result.Endpoints[j].Endpoint })
+	sort.Slice(result.Serialization, func(i, j int) bool {
+		a, b := result.Serialization[i], result.Serialization[j]
+		if a.Codec != b.Codec {
+			return a.Codec < b.Codec
+		}
+		return a.Op > b.Op
+	})
+	return result
+}
+
+// serverCompressionDelta is the time the server spent compressing and
+// decompressing between two scrapes.
+func serverCompressionDelta(before, after *serverMetricsSnapshot) compression.Stats {
+	delta := func(direction string) time.Duration {
+		key := metricCompression + "\t" + direction
+		return seconds(after.values[key] - before.values[key])
+	}
+	return compression.Stats{
+		CompressTime:   delta("compress"),
+		DecompressTime: delta("decompress"),
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/certs"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"google.golang.org/grpc/credentials"
+)
+
+// restBaseURL is the REST server address, https when TLS is enabled.
+func restBaseURL(config entity.Config) string {
+	if config.TLSMode != "" {
+		return "https://localhost:8080"
+	}
+	return "http://localhost:8080"
+}
+
+// clientTLSConfig loads the certificates the server generated into
+// config.TLSDir. It returns nil when TLS is disabled.
+func clientTLSConfig(config entity.Config) (*tls.Config, error) {
+	if !certs.ValidMode(config.TLSMode) {
+		return nil, fmt.Errorf("unsupported TLS mode %q, use %q or %q", config.TLSMode, certs.ModeTLS, certs.ModeMutual)
+	}
+	if config.TLSMode == "" {
+		return nil, nil
+	}
+	return certs.ClientConfig(config.TLSDir, config.TLSMode == certs.ModeMutual)
+}
+
+// admin is the client adminClient hands out with TLS enabled, shared so its
+// connections are reused across runs instead of piling up on the server.
+var admin struct {
+	sync.Mutex
+	client *http.Client
+}
+
+// adminClient is a plain HTTP client for talking to the server outside of
+// the measured requests.
+func adminClient(config entity.Config) (*http.Client, error) {
+	tlsConfig, err := clientTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return http.DefaultClient, nil
+	}
+
+	admin.Lock()
+	defer admin.Unlock()
+	if admin.client == nil {
+		admin.client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+	return admin.client, nil
+}
+
+// resetAdminClient closes the TLS admin client's connections and drops it,
+// so the next one loads the certificates again.
+func resetAdminClient() {
+	admin.Lock()
+	defer admin.Unlock()
+	if admin.client != nil {
+		admin.client.CloseIdleConnections()
+		admin.client = nil
+	}
+}
+
+// handshakes collects the duration of every TLS handshake the drivers make,
+// so handshake cost can be reported apart from request latency.
+var handshakes struct {
+	sync.Mutex
+	durations []time.Duration
+}
+
+func recordHandshake(d time.Duration) {
+	handshakes.Lock()
+	defer handshakes.Unlock()
+	handshakes.durations = append(handshakes.durations, d)
+}
+
+// drainHandshakes returns the handshakes recorded since the last call.
+func drainHandshakes() []time.Duration {
+	handshakes.Lock()
+	defer handshakes.Unlock()
+	durations := handshakes.durations
+	handshakes.durations = nil
+	return durations
+}
+
+// dialTLSCounting returns a dial function for http.Transport that counts
+// the connection's traffic and times the TLS handshake.
+func dialTLSCounting(config *tls.Config) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialCounting(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		config := config.Clone()
+		config.ServerName = host
+
+		tlsConn := tls.Client(conn, config)
+		start := time.Now()
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		recordHandshake(time.Since(start))
+		return tlsConn, nil
+	}
+}
+
+// timedCredentials times the client side of gRPC TLS handshakes.
+type timedCredentials struct {
+	credentials.TransportCredentials
+}
+
+func (c timedCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	start := time.Now()
+	conn, info, err := c.TransportCredentials.ClientHandshake(ctx, authority, conn)
+	if err == nil {
+		recordHandshake(time.Since(start))
+	}
+	return conn, info, err
+}
+
+func (c timedCredentials) Clone() credentials.TransportCredentials {
+	return timedCredentials{c.TransportCredentials.Clone()}
+}
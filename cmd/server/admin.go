@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/profiling"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/resources"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type setDatasetRequest struct {
+	Size       int  `json:"size"`
+	Regenerate bool `json:"regenerate"`
+}
+
+type setDatasetResponse struct {
+	Size          int `json:"size"`
+	JSONBytes     int `json:"json_bytes"`
+	ProtobufBytes int `json:"protobuf_bytes"`
+}
+
+// REST admin handler for switching the served dataset
+func handleSetDataset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req setDatasetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ds, err := switchDataset(req.Size, req.Regenerate)
+	if errors.Is(err, errInvalidDatasetSize) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		log.Printf("Failed to switch dataset: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Switched dataset to size %d", ds.size)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(setDatasetResponse{
+		Size:          ds.size,
+		JSONBytes:     len(ds.jsonData),
+		ProtobufBytes: len(ds.rawData),
+	})
+}
+
+// resourceHistory is how far back the server keeps its resource samples.
+const resourceHistory = time.Hour
+
+// resourceSampler samples the server's CPU and memory usage from startup.
+var resourceSampler *resources.Sampler
+
+// REST admin handler returning the server's resource samples since the time
+// in the since parameter, in RFC 3339 format
+func handleGetResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+	if err != nil {
+		http.Error(w, "Invalid since", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resourceSampler.Since(since))
+}
+
+// REST admin handler starting a profiling session, stopped and collected
+// with handleStopProfile
+func handleStartProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := profiling.Start(); errors.Is(err, profiling.ErrRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Printf("Failed to start profiling: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// REST admin handler stopping the profiling session and returning its
+// profiles, in pprof format by name
+func handleStopProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	profiles, err := profiling.Stop()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profiles)
+}
+
+// gRPC admin server implementation
+type adminServer struct {
+	pb.UnimplementedAdminServiceServer
+}
+
+func (s *adminServer) SetDataset(ctx context.Context, req *pb.SetDatasetRequest) (*pb.SetDatasetResponse, error) {
+	ds, err := switchDataset(int(req.Size), req.Regenerate)
+	if errors.Is(err, errInvalidDatasetSize) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		log.Printf("Failed to switch dataset: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.Printf("Switched dataset to size %d", ds.size)
+
+	return &pb.SetDatasetResponse{
+		Size:          int32(ds.size),
+		JsonBytes:     int64(len(ds.jsonData)),
+		ProtobufBytes: int64(len(ds.rawData)),
+	}, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+// dataset is the response cache shared by both servers. It is never mutated
+// after being stored, so handlers can keep using a dataset they loaded while
+// a new one is swapped in.
+type dataset struct {
+	size         int
+	jsonResponse *entity.GetPopulationResponse
+	pbResponse   *pb.GetPopulationResponse
+	rawData      []byte
+	jsonData     []byte
+	// codecData holds the fixture bytes of every other codec, by name
+	codecData map[string][]byte
+}
+
+// maxDatasetSize bounds the population a dataset can hold, so a single admin
+// request can't exhaust the server's memory.
+const maxDatasetSize = 50000
+
+// errInvalidDatasetSize is returned for sizes outside 1 to maxDatasetSize.
+var errInvalidDatasetSize = errors.New("invalid dataset size")
+
+var (
+	currentDataset atomic.Pointer[dataset]
+	// datasetMu serializes reloads, since they share the fixture files on disk
+	datasetMu sync.Mutex
+)
+
+// loadDataset reads the fixtures for size, generating them first when asked
+// to or when they don't exist yet.
+func loadDataset(size int, regenerate bool) (*dataset, error) {
+	if size <= 0 || size > maxDatasetSize {
+		return nil, fmt.Errorf("%w %d, must be between 1 and %d", errInvalidDatasetSize, size, maxDatasetSize)
+	}
+
+	if !regenerate {
+		for _, name := range codec.Names() {
+			c, _ := codec.Lookup(name)
+			if _, err := os.Stat(testutil.FixturePath(size, c.Extension)); errors.Is(err, fs.ErrNotExist) {
+				regenerate = true
+			}
+		}
+	}
+	if regenerate {
+		if err := testutil.GenerateFixtures([]int{size}); err != nil {
+			return nil, fmt.Errorf("generate fixtures: %w", err)
+		}
+	}
+
+	// Load JSON data
+	jsonData, err := os.ReadFile(testutil.FixturePath(size, "json"))
+	if err != nil {
+		return nil, err
+	}
+	jsonResponse := &entity.GetPopulationResponse{}
+	if err := json.Unmarshal(jsonData, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	// Load protobuf data
+	pbData, err := os.ReadFile(testutil.FixturePath(size, "pb"))
+	if err != nil {
+		return nil, err
+	}
+	pbResponse := &pb.GetPopulationResponse{}
+	if err := proto.Unmarshal(pbData, pbResponse); err != nil {
+		return nil, err
+	}
+
+	// Cache the exact bytes /benchmark would encode, for /benchmark/raw
+	jsonCodec, _ := codec.Lookup(codec.JSON)
+	var jsonBuf bytes.Buffer
+	if err := jsonCodec.Encode(&jsonBuf, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	codecData := make(map[string][]byte)
+	for _, name := range codec.Names() {
+		c, _ := codec.Lookup(name)
+		if name == codec.JSON || c.Proto {
+			continue
+		}
+		data, err := os.ReadFile(testutil.FixturePath(size, c.Extension))
+		if err != nil {
+			return nil, err
+		}
+		codecData[name] = data
+	}
+
+	return &dataset{
+		size:         size,
+		jsonResponse: jsonResponse,
+		pbResponse:   pbResponse,
+		rawData:      pbData,
+		jsonData:     jsonBuf.Bytes(),
+		codecData:    codecData,
+	}, nil
+}
+
+// encoded returns the cached encoding of the whole population for c, if
+// there is one.
+func (ds *dataset) encoded(c codec.Codec) ([]byte, bool) {
+	switch c.Name {
+	case codec.JSON:
+		return ds.jsonData, true
+	case codec.Protobuf:
+		return ds.rawData, true
+	}
+	data, ok := ds.codecData[c.Name]
+	return data, ok
+}
+
+// switchDataset loads a dataset and atomically makes it the one being served.
+// In-flight requests finish with the dataset they started with.
+func switchDataset(size int, regenerate bool) (*dataset, error) {
+	datasetMu.Lock()
+	defer datasetMu.Unlock()
+
+	ds, err := loadDataset(size, regenerate)
+	if err != nil {
+		return nil, err
+	}
+	currentDataset.Store(ds)
+	return ds, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// fieldTree is a parsed field mask. A nil subtree selects the whole field.
+// Person's protobuf field names match its JSON tags, so the same tree
+// projects both representations.
+type fieldTree map[string]fieldTree
+
+// parseFields validates paths such as "id" or "address.city" against Person.
+func parseFields(paths []string) (fieldTree, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	mask, err := fieldmaskpb.New(&pb.Person{}, paths...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	mask.Normalize()
+
+	tree := fieldTree{}
+	for _, path := range mask.Paths {
+		node := tree
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if node[name] == nil {
+				node[name] = fieldTree{}
+			}
+			node = node[name]
+		}
+	}
+	return tree, nil
+}
+
+// projectProto copies the selected fields of src into a new message.
+func projectProto(src protoreflect.Message, tree fieldTree) protoreflect.Message {
+	dst := src.New()
+	fields := src.Descriptor().Fields()
+	for name, subtree := range tree {
+		field := fields.ByName(protoreflect.Name(name))
+		if !src.Has(field) {
+			continue
+		}
+		if subtree == nil || field.Message() == nil {
+			dst.Set(field, src.Get(field))
+			continue
+		}
+		dst.Set(field, protoreflect.ValueOfMessage(projectProto(src.Get(field).Message(), subtree)))
+	}
+	return dst
+}
+
+func projectPerson(person *pb.Person, tree fieldTree) *pb.Person {
+	return projectProto(person.ProtoReflect(), tree).Interface().(*pb.Person)
+}
+
+// projectJSON returns the selected fields of a struct keyed by JSON name, a
+// nil tree selecting them all. Nested structs are flattened into maps too, so
+// codecs that need concrete types registered, like gob, can encode them.
+func projectJSON(v reflect.Value, tree fieldTree) map[string]interface{} {
+	projected := make(map[string]interface{}, v.NumField())
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		var subtree fieldTree
+		if tree != nil {
+			var ok bool
+			if subtree, ok = tree[name]; !ok {
+				continue
+			}
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			projected[name] = projectJSON(field, subtree)
+			continue
+		}
+		projected[name] = field.Interface()
+	}
+	return projected
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// testPerson builds a person with the fields the tests filter and project on.
+func testPerson(id, role, state, city string, active bool) entity.Person {
+	return entity.Person{
+		ID:        id,
+		FirstName: "First " + id,
+		Role:      role,
+		Active:    active,
+		Address: entity.Address{
+			Street:  id + " Main Street",
+			City:    city,
+			State:   state,
+			Country: "USA",
+		},
+		Preferences: map[string]interface{}{"language": "en"},
+	}
+}
+
+// newTestDataset holds people in both representations, in the same order
+// like generated fixtures.
+func newTestDataset(people ...entity.Person) *dataset {
+	ds := &dataset{
+		size:         len(people),
+		jsonResponse: &entity.GetPopulationResponse{Population: people},
+		pbResponse:   &pb.GetPopulationResponse{},
+	}
+	for _, person := range people {
+		ds.pbResponse.Population = append(ds.pbResponse.Population, &pb.Person{
+			Id:        person.ID,
+			FirstName: person.FirstName,
+			Role:      person.Role,
+			Active:    person.Active,
+			Address: &pb.Address{
+				Street:  person.Address.Street,
+				City:    person.Address.City,
+				State:   person.Address.State,
+				Country: person.Address.Country,
+			},
+			Preferences: map[string]*pb.Value{"language": {Kind: &pb.Value_StringValue{StringValue: "en"}}},
+		})
+	}
+	return ds
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		want    fieldTree
+		wantErr bool
+	}{
+		{name: "empty mask", paths: nil, want: nil},
+		{name: "top-level field", paths: []string{"id"}, want: fieldTree{"id": nil}},
+		{name: "nested field", paths: []string{"address.city"}, want: fieldTree{"address": {"city": nil}}},
+		{name: "nested fields merged", paths: []string{"address.city", "address.state", "id"}, want: fieldTree{"id": nil, "address": {"city": nil, "state": nil}}},
+		{name: "whole message covers its fields", paths: []string{"address", "address.city"}, want: fieldTree{"address": nil}},
+		{name: "unknown field", paths: []string{"salary"}, wantErr: true},
+		{name: "unknown nested field", paths: []string{"address.planet"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFields(tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFields(%v) error = %v, want error %v", tt.paths, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFields(%v) = %v, want %v", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	ds := newTestDataset(testPerson("1", "admin", "CA", "Los Angeles", true))
+	fields, err := parseFields([]string{"id", "address.city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotJSON := projectJSON(reflect.ValueOf(ds.jsonResponse.Population[0]), fields)
+	wantJSON := map[string]interface{}{
+		"id":      "1",
+		"address": map[string]interface{}{"city": "Los Angeles"},
+	}
+	if !reflect.DeepEqual(gotJSON, wantJSON) {
+		t.Errorf("projectJSON() = %v, want %v", gotJSON, wantJSON)
+	}
+
+	gotPB := projectPerson(ds.pbResponse.Population[0], fields)
+	wantPB := &pb.Person{Id: "1", Address: &pb.Address{City: "Los Angeles"}}
+	if !proto.Equal(gotPB, wantPB) {
+		t.Errorf("projectPerson() = %v, want %v", gotPB, wantPB)
+	}
+}
+
+// TestProjectionEveryCodec encodes and decodes projected responses with
+// every registered codec, including projections of whole nested messages.
+func TestProjectionEveryCodec(t *testing.T) {
+	ds := newTestDataset(testPerson("1", "admin", "CA", "Los Angeles", true))
+
+	tests := []struct {
+		fields []string
+		// want maps an address field to its value, empty when the address
+		// isn't selected
+		want map[string]string
+	}{
+		{fields: []string{"address"}, want: map[string]string{"city": "Los Angeles", "street": "1 Main Street"}},
+		{fields: []string{"address.city"}, want: map[string]string{"city": "Los Angeles", "street": ""}},
+		{fields: []string{"id", "preferences"}, want: nil},
+	}
+
+	for _, name := range codec.Names() {
+		c, _ := codec.Lookup(name)
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.fields[0], func(t *testing.T) {
+				fields, err := parseFields(tt.fields)
+				if err != nil {
+					t.Fatal(err)
+				}
+				query := populationQuery{fields: fields}
+
+				var buf bytes.Buffer
+				if err := c.Encode(&buf, query.response(ds, c)); err != nil {
+					t.Fatalf("encode: %v", err)
+				}
+
+				if c.Proto {
+					var resp pb.GetPopulationResponse
+					if err := c.Decode(buf.Bytes(), &resp); err != nil {
+						t.Fatalf("decode: %v", err)
+					}
+					address := resp.Population[0].GetAddress()
+					for field, want := range tt.want {
+						got := map[string]string{"city": address.GetCity(), "street": address.GetStreet()}[field]
+						if got != want {
+							t.Errorf("address.%s = %q, want %q", field, got, want)
+						}
+					}
+					if tt.want == nil && address != nil {
+						t.Errorf("address = %v, want it left out", address)
+					}
+					return
+				}
+
+				var resp struct {
+					Population []map[string]interface{} `json:"population"`
+				}
+				if err := c.Decode(buf.Bytes(), &resp); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				if len(resp.Population) != 1 {
+					t.Fatalf("got %d people, want 1", len(resp.Population))
+				}
+				projected := resp.Population[0]
+				if tt.want == nil {
+					if _, ok := projected["address"]; ok {
+						t.Errorf("address = %v, want it left out", projected["address"])
+					}
+					if _, ok := projected["preferences"]; !ok {
+						t.Errorf("preferences missing from %v", projected)
+					}
+					return
+				}
+
+				address, ok := projected["address"].(map[string]interface{})
+				if !ok {
+					t.Fatalf("address = %#v, want a map", projected["address"])
+				}
+				for field, want := range tt.want {
+					got, _ := address[field].(string)
+					if got != want {
+						t.Errorf("address.%s = %q, want %q", field, got, want)
+					}
+				}
+			})
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,63 +10,132 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/certs"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
 	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
 	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
-	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/testutil"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/resources"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
-// Global response cache for both servers
-var (
-	globalJSONResponse *entity.GetPopulationResponse
-	globalPBResponse   *pb.GetPopulationResponse
-	globalRawData      []byte
-)
+// nextPageTokenHeader carries the next page token of streamed responses,
+// which have no envelope to put it in.
+const nextPageTokenHeader = "X-Next-Page-Token"
 
-// Initialize loads the data at startup
-func initialize(size int) error {
-	// Generate fixtures for different sizes
-	testutil.GenerateFixtures([]int{size})
+// REST handler
+func handleGetBenchmark(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	// Load JSON data
-	jsonData, err := os.ReadFile(fmt.Sprintf("testutil/fixtures/fixtures_population_%d.json", size))
+	query, err := queryFromURL(r.URL.Query())
 	if err != nil {
-		return err
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c, ok := codec.Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("Not acceptable, available: %v", codec.Names()), http.StatusNotAcceptable)
+		return
 	}
-	globalJSONResponse = &entity.GetPopulationResponse{}
-	if err := json.Unmarshal(jsonData, globalJSONResponse); err != nil {
-		return err
+
+	w.Header().Set("Content-Type", c.ContentType)
+	if err := writeEncoded(w, c, query.response(currentDataset.Load(), c)); err != nil {
+		log.Printf("Failed to encode %s response: %v", c.Name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// REST handler serving pre-serialized responses, the counterpart of
+// GetPopulationRaw
+func handleGetBenchmarkRaw(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	// Load protobuf data
-	pbData, err := os.ReadFile(fmt.Sprintf("testutil/fixtures/fixtures_population_%d.pb", size))
+	query, err := queryFromURL(r.URL.Query())
 	if err != nil {
-		return err
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	globalRawData = pbData
-	globalPBResponse = &pb.GetPopulationResponse{}
-	if err := proto.Unmarshal(pbData, globalPBResponse); err != nil {
-		return err
+
+	c, ok := codec.Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("Not acceptable, available: %v", codec.Names()), http.StatusNotAcceptable)
+		return
 	}
 
-	return nil
+	ds := currentDataset.Load()
+	w.Header().Set("Content-Type", c.ContentType)
+	if data, cached := ds.encoded(c); cached && query.isEmpty() {
+		w.Write(data)
+		return
+	}
+	if err := writeEncoded(w, c, query.response(ds, c)); err != nil {
+		log.Printf("Failed to encode %s response: %v", c.Name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
-// REST handler
-func handleGetBenchmark(w http.ResponseWriter, r *http.Request) {
+// REST handler streaming people as newline-delimited JSON with chunked
+// transfer, flushing every batch_size records. It takes the same query
+// parameters as /benchmark, the next page token is sent as a header.
+func handleGetBenchmarkStream(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(globalJSONResponse)
+	query, err := queryFromURL(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batchSize := 1
+	if value := r.URL.Query().Get("batch_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid batch_size", http.StatusBadRequest)
+			return
+		}
+		batchSize = max(size, 1)
+	}
+
+	count, person, nextPageToken := query.streamPeople(currentDataset.Load())
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if nextPageToken != "" {
+		w.Header().Set(nextPageTokenHeader, nextPageToken)
+	}
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	for i := 0; i < count; i++ {
+		if err := encoder.Encode(person(i)); err != nil {
+			return
+		}
+		if flusher != nil && ((i+1)%batchSize == 0 || i == count-1) {
+			flusher.Flush()
+		}
+	}
 }
 
 // gRPC server implementation
@@ -74,11 +144,66 @@ type grpcServer struct {
 }
 
 func (s *grpcServer) GetPopulation(ctx context.Context, req *pb.GetPopulationRequest) (*pb.GetPopulationResponse, error) {
-	return globalPBResponse, nil
+	query, err := queryFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ds := currentDataset.Load()
+	if query.isEmpty() {
+		return ds.pbResponse, nil
+	}
+	return query.pbResponse(ds), nil
 }
 
 func (s *grpcServer) GetPopulationRaw(ctx context.Context, req *pb.GetPopulationRequest) (*pb.RawResponse, error) {
-	return &pb.RawResponse{Data: globalRawData}, nil
+	query, err := queryFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ds := currentDataset.Load()
+	if query.isEmpty() {
+		return &pb.RawResponse{Data: ds.rawData}, nil
+	}
+
+	data, err := proto.Marshal(query.pbResponse(ds))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.RawResponse{Data: data}, nil
+}
+
+func (s *grpcServer) StreamPopulation(req *pb.StreamPopulationRequest, stream grpc.ServerStreamingServer[pb.GetPopulationResponse]) error {
+	query, err := queryFromRequest(req.Query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ds := currentDataset.Load()
+	population := ds.pbResponse.Population
+	nextPageToken := ""
+	if !query.isEmpty() {
+		resp := query.pbResponse(ds)
+		population, nextPageToken = resp.Population, resp.NextPageToken
+	}
+
+	batchSize := int(req.BatchSize)
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	for start := 0; start < len(population); start += batchSize {
+		end := min(start+batchSize, len(population))
+		batch := &pb.GetPopulationResponse{Population: population[start:end]}
+		if end == len(population) {
+			batch.NextPageToken = nextPageToken
+		}
+		if err := stream.Send(batch); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func main() {
@@ -87,22 +212,59 @@ func main() {
 		log.Fatalf("Failed to parse environment variables: %v", err)
 	}
 
+	if err := codec.UseJSON(config.JSONEncoder); err != nil {
+		log.Fatalf("Failed to select JSON encoder: %v", err)
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLSMode != "" {
+		if !certs.ValidMode(config.TLSMode) {
+			log.Fatalf("Unsupported TLS mode %q, use %q or %q", config.TLSMode, certs.ModeTLS, certs.ModeMutual)
+		}
+		if err := certs.Generate(config.TLSDir); err != nil {
+			log.Fatalf("Failed to generate certificates: %v", err)
+		}
+		var err error
+		tlsConfig, err = certs.ServerConfig(config.TLSDir, config.TLSMode == certs.ModeMutual)
+		if err != nil {
+			log.Fatalf("Failed to load certificates: %v", err)
+		}
+		log.Printf("Generated %s certificates in %s", config.TLSMode, config.TLSDir)
+	}
+
+	if config.ResourceSampleInterval <= 0 {
+		log.Fatalf("RESOURCE_SAMPLE_INTERVAL must be positive")
+	}
+	resourceSampler = resources.StartSampler(config.ResourceSampleInterval, int(resourceHistory/config.ResourceSampleInterval))
+
 	// Load data at startup
-	if err := initialize(config.MockSize); err != nil {
+	if _, err := switchDataset(config.MockSize, true); err != nil {
 		log.Fatalf("Failed to initialize: %v", err)
 	}
 
 	// Create REST server
 	handler := http.NewServeMux()
 	handler.HandleFunc("/benchmark", handleGetBenchmark)
+	handler.HandleFunc("/benchmark/raw", handleGetBenchmarkRaw)
+	handler.HandleFunc("/benchmark/stream", handleGetBenchmarkStream)
+	handler.HandleFunc("/population", handleUploadPopulation)
+	handler.HandleFunc("/admin/dataset", handleSetDataset)
+	handler.HandleFunc("/admin/resources", handleGetResources)
+	handler.HandleFunc("/admin/profile/start", handleStartProfile)
+	handler.HandleFunc("/admin/profile/stop", handleStopProfile)
+	// Left uncompressed, like the admin routes, see compressBenchmarkRoutes
+	handler.Handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{DisableCompression: true}))
 
 	restServer := &http.Server{
-		Addr:              ":8080",
-		Handler:           handler,
+		Addr: ":8080",
+		// Accept cleartext HTTP/2 alongside HTTP/1.1, over TLS HTTP/2 is
+		// negotiated through ALPN
+		Handler:           h2c.NewHandler(instrumentHTTP(handler, compressBenchmarkRoutes(handler)), &http2.Server{}),
 		ReadTimeout:       5 * time.Second,
 		WriteTimeout:      5 * time.Second,
 		IdleTimeout:       120 * time.Second,
 		ReadHeaderTimeout: 2 * time.Second,
+		TLSConfig:         tlsConfig,
 	}
 
 	// Create gRPC server
@@ -111,9 +273,9 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcSrv := grpc.NewServer(
-		grpc.MaxRecvMsgSize(1024*1024*10),
-		grpc.MaxSendMsgSize(1024*1024*10),
+	grpcOptions := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(1024 * 1024 * 10),
+		grpc.MaxSendMsgSize(1024 * 1024 * 10),
 		grpc.MaxConcurrentStreams(100000),
 		grpc.NumStreamWorkers(32),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
@@ -122,13 +284,29 @@ func main() {
 			Time:                  30 * time.Second,
 			Timeout:               20 * time.Second,
 		}),
-	)
+		grpc.ChainUnaryInterceptor(unaryMetricsInterceptor),
+		grpc.ChainStreamInterceptor(streamMetricsInterceptor),
+		grpc.StatsHandler(bytesStatsHandler{}),
+		grpc.ForceServerCodecV2(newTimedServerCodec()),
+	}
+	if tlsConfig != nil {
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcSrv := grpc.NewServer(grpcOptions...)
 	pb.RegisterPopulationServiceServer(grpcSrv, &grpcServer{})
+	pb.RegisterAdminServiceServer(grpcSrv, &adminServer{})
 
 	// Start both servers
 	go func() {
 		log.Printf("Starting REST server on port 8080")
-		if err := restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig != nil {
+			// Certificates come from TLSConfig
+			err = restServer.ListenAndServeTLS("", "")
+		} else {
+			err = restServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Printf("REST server error: %v", err)
 		}
 	}()
@@ -153,3 +331,17 @@ func main() {
 		log.Printf("Error shutting down REST server: %v", err)
 	}
 }
+
+// compressBenchmarkRoutes applies compression.Middleware to every route but
+// /metrics and the admin API, so the client's scrapes and admin calls around
+// a run don't add to the server's compression time.
+func compressBenchmarkRoutes(mux *http.ServeMux) http.Handler {
+	compressed := compression.Middleware(mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || strings.HasPrefix(r.URL.Path, "/admin/") {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		compressed.ServeHTTP(w, r)
+	})
+}
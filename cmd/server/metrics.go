@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/compression"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	transportREST = "rest"
+	transportGRPC = "grpc"
+)
+
+// Serialization operations: encoding responses and decoding requests.
+const (
+	opEncode = "encode"
+	opDecode = "decode"
+)
+
+// Buckets from 100µs to about 13s.
+var durationBuckets = prometheus.ExponentialBuckets(0.0001, 2, 18)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "benchmark",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Requests handled, by transport, endpoint and status code.",
+	}, []string{"transport", "endpoint", "code"})
+
+	handlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "benchmark",
+		Subsystem: "server",
+		Name:      "handler_duration_seconds",
+		Help:      "Time from receiving a request until its handler returned.",
+		Buckets:   durationBuckets,
+	}, []string{"transport", "endpoint"})
+
+	serializationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "benchmark",
+		Subsystem: "server",
+		Name:      "serialization_duration_seconds",
+		Help:      "Time spent encoding response bodies and decoding request bodies, by codec and operation.",
+		Buckets:   durationBuckets,
+	}, []string{"transport", "codec", "op"})
+
+	bytesWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "benchmark",
+		Subsystem: "server",
+		Name:      "bytes_written_total",
+		Help:      "Response bytes written, after compression.",
+	}, []string{"transport", "endpoint"})
+
+	inFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "benchmark",
+		Subsystem: "server",
+		Name:      "in_flight_requests",
+		Help:      "Requests currently being handled.",
+	}, []string{"transport", "endpoint"})
+
+	// Compression time is tracked by the compression package for both
+	// transports and read when scraped
+	_ = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   "benchmark",
+		Subsystem:   "server",
+		Name:        "compression_seconds_total",
+		Help:        "Time spent compressing responses and decompressing requests, by direction.",
+		ConstLabels: prometheus.Labels{"direction": "compress"},
+	}, func() float64 { return compression.ReadStats().CompressTime.Seconds() })
+
+	_ = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   "benchmark",
+		Subsystem:   "server",
+		Name:        "compression_seconds_total",
+		Help:        "Time spent compressing responses and decompressing requests, by direction.",
+		ConstLabels: prometheus.Labels{"direction": "decompress"},
+	}, func() float64 { return compression.ReadStats().DecompressTime.Seconds() })
+)
+
+// instrumentHTTP records metrics for every request, labelled by the mux
+// pattern that handles it.
+func instrumentHTTP(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, endpoint := mux.Handler(r)
+		if endpoint == "" {
+			endpoint = "other"
+		}
+
+		gauge := inFlight.WithLabelValues(transportREST, endpoint)
+		gauge.Inc()
+		defer gauge.Dec()
+
+		start := time.Now()
+		writer := &metricsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(writer, r)
+
+		handlerDuration.WithLabelValues(transportREST, endpoint).Observe(time.Since(start).Seconds())
+		bytesWritten.WithLabelValues(transportREST, endpoint).Add(float64(writer.bytes))
+		requestsTotal.WithLabelValues(transportREST, endpoint, strconv.Itoa(writer.status)).Inc()
+	})
+}
+
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (m *metricsResponseWriter) WriteHeader(status int) {
+	m.status = status
+	m.ResponseWriter.WriteHeader(status)
+}
+
+func (m *metricsResponseWriter) Write(p []byte) (int, error) {
+	n, err := m.ResponseWriter.Write(p)
+	m.bytes += int64(n)
+	return n, err
+}
+
+func (m *metricsResponseWriter) Flush() {
+	if flusher, ok := m.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// writeEncoded encodes v with c into a buffer, so encoding is timed apart
+// from writing, then writes it to w. Only encoding errors are returned, as
+// nothing has been written yet when they happen; a failed write means the
+// client went away.
+func writeEncoded(w io.Writer, c codec.Codec, v any) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
+
+	start := time.Now()
+	if err := c.Encode(buf, v); err != nil {
+		return err
+	}
+	serializationDuration.WithLabelValues(transportREST, c.Name, opEncode).Observe(time.Since(start).Seconds())
+
+	w.Write(buf.Bytes())
+	return nil
+}
+
+// decodeTimed decodes a request body that has already been read with c, so
+// the time spent waiting on the client is left out, as for gRPC.
+func decodeTimed(c codec.Codec, data []byte, v any) error {
+	start := time.Now()
+	err := c.Decode(data, v)
+	serializationDuration.WithLabelValues(transportREST, c.Name, opDecode).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func unaryMetricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	gauge := inFlight.WithLabelValues(transportGRPC, info.FullMethod)
+	gauge.Inc()
+	defer gauge.Dec()
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	handlerDuration.WithLabelValues(transportGRPC, info.FullMethod).Observe(time.Since(start).Seconds())
+	requestsTotal.WithLabelValues(transportGRPC, info.FullMethod, status.Code(err).String()).Inc()
+	return resp, err
+}
+
+func streamMetricsInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	gauge := inFlight.WithLabelValues(transportGRPC, info.FullMethod)
+	gauge.Inc()
+	defer gauge.Dec()
+
+	start := time.Now()
+	err := handler(srv, stream)
+	handlerDuration.WithLabelValues(transportGRPC, info.FullMethod).Observe(time.Since(start).Seconds())
+	requestsTotal.WithLabelValues(transportGRPC, info.FullMethod, status.Code(err).String()).Inc()
+	return err
+}
+
+// timedServerCodec times the unmarshalling of gRPC requests and the
+// marshalling of responses, which happen before the interceptors run and
+// after the handler returns, so neither is part of the handler duration.
+type timedServerCodec struct {
+	encoding.CodecV2
+}
+
+func newTimedServerCodec() timedServerCodec {
+	return timedServerCodec{encoding.GetCodecV2(grpcproto.Name)}
+}
+
+func (t timedServerCodec) Marshal(v any) (mem.BufferSlice, error) {
+	start := time.Now()
+	data, err := t.CodecV2.Marshal(v)
+	serializationDuration.WithLabelValues(transportGRPC, grpcproto.Name, opEncode).Observe(time.Since(start).Seconds())
+	return data, err
+}
+
+func (t timedServerCodec) Unmarshal(data mem.BufferSlice, v any) error {
+	start := time.Now()
+	err := t.CodecV2.Unmarshal(data, v)
+	serializationDuration.WithLabelValues(transportGRPC, grpcproto.Name, opDecode).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// bytesStatsHandler counts the bytes of gRPC responses as sent on the wire.
+type bytesStatsHandler struct{}
+
+type methodKey struct{}
+
+func (bytesStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (bytesStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if out, ok := s.(*stats.OutPayload); ok {
+		method, _ := ctx.Value(methodKey{}).(string)
+		bytesWritten.WithLabelValues(transportGRPC, method).Add(float64(out.WireLength))
+	}
+}
+
+func (bytesStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (bytesStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+)
+
+// populationQuery is the filter and page requested by a client. REST query
+// parameters and GetPopulationRequest are both parsed into it so the two
+// servers return identical pages.
+type populationQuery struct {
+	role     string
+	active   *bool
+	state    string
+	city     string
+	pageSize int
+	offset   int
+	fields   fieldTree
+}
+
+// queryFromRequest parses req, a nil request selecting everything.
+func queryFromRequest(req *pb.GetPopulationRequest) (populationQuery, error) {
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return populationQuery{}, err
+	}
+	if req.GetPageSize() < 0 {
+		return populationQuery{}, fmt.Errorf("invalid page_size %d", req.GetPageSize())
+	}
+
+	fields, err := parseFields(req.GetFieldMask().GetPaths())
+	if err != nil {
+		return populationQuery{}, err
+	}
+
+	query := populationQuery{
+		role:     req.GetRole(),
+		state:    req.GetState(),
+		city:     req.GetCity(),
+		pageSize: int(req.GetPageSize()),
+		offset:   offset,
+		fields:   fields,
+	}
+	if req != nil {
+		query.active = req.Active
+	}
+	return query, nil
+}
+
+func queryFromURL(values url.Values) (populationQuery, error) {
+	query := populationQuery{
+		role:  values.Get("role"),
+		state: values.Get("state"),
+		city:  values.Get("city"),
+	}
+
+	if value := values.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return populationQuery{}, fmt.Errorf("invalid active %q", value)
+		}
+		query.active = &active
+	}
+	if value := values.Get("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 0 {
+			return populationQuery{}, fmt.Errorf("invalid page_size %q", value)
+		}
+		query.pageSize = pageSize
+	}
+
+	if value := values.Get("fields"); value != "" {
+		fields, err := parseFields(strings.Split(value, ","))
+		if err != nil {
+			return populationQuery{}, err
+		}
+		query.fields = fields
+	}
+
+	offset, err := decodePageToken(values.Get("page_token"))
+	if err != nil {
+		return populationQuery{}, err
+	}
+	query.offset = offset
+	return query, nil
+}
+
+// isEmpty reports whether the query selects the whole dataset, which lets
+// handlers serve their cached responses.
+func (q populationQuery) isEmpty() bool {
+	return q.role == "" && q.active == nil && q.state == "" && q.city == "" &&
+		q.pageSize == 0 && q.offset == 0 && q.fields == nil
+}
+
+func (q populationQuery) matches(person *entity.Person) bool {
+	return (q.role == "" || person.Role == q.role) &&
+		(q.active == nil || person.Active == *q.active) &&
+		(q.state == "" || person.Address.State == q.state) &&
+		(q.city == "" || person.Address.City == q.city)
+}
+
+// apply returns the dataset indices of the requested page and the token of
+// the page after it. Indices are shared by the JSON and protobuf populations,
+// which are generated in the same order.
+func (q populationQuery) apply(ds *dataset) ([]int, string) {
+	population := ds.jsonResponse.Population
+
+	indices := make([]int, 0)
+	matched := 0
+	for i := range population {
+		if !q.matches(&population[i]) {
+			continue
+		}
+		matched++
+		if matched <= q.offset {
+			continue
+		}
+		if q.pageSize > 0 && len(indices) == q.pageSize {
+			return indices, encodePageToken(q.offset + q.pageSize)
+		}
+		indices = append(indices, i)
+	}
+	return indices, ""
+}
+
+// projectedJSONResponse is returned instead of entity.GetPopulationResponse
+// when only some fields were requested.
+type projectedJSONResponse struct {
+	Population    []map[string]interface{} `json:"population"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+}
+
+// response returns the population matching q in the types c encodes. An
+// empty query returns the cached response as is.
+func (q populationQuery) response(ds *dataset, c codec.Codec) any {
+	switch {
+	case c.Proto && q.isEmpty():
+		return ds.pbResponse
+	case c.Proto:
+		return q.pbResponse(ds)
+	case q.isEmpty():
+		return ds.jsonResponse
+	}
+	return q.jsonResponse(ds)
+}
+
+func (q populationQuery) jsonResponse(ds *dataset) interface{} {
+	indices, nextPageToken := q.apply(ds)
+
+	if q.fields != nil {
+		resp := &projectedJSONResponse{
+			Population:    make([]map[string]interface{}, len(indices)),
+			NextPageToken: nextPageToken,
+		}
+		for i, index := range indices {
+			resp.Population[i] = projectJSON(reflect.ValueOf(ds.jsonResponse.Population[index]), q.fields)
+		}
+		return resp
+	}
+
+	resp := &entity.GetPopulationResponse{
+		Population:    make([]entity.Person, len(indices)),
+		NextPageToken: nextPageToken,
+	}
+	for i, index := range indices {
+		resp.Population[i] = ds.jsonResponse.Population[index]
+	}
+	return resp
+}
+
+func (q populationQuery) pbResponse(ds *dataset) *pb.GetPopulationResponse {
+	indices, nextPageToken := q.apply(ds)
+
+	resp := &pb.GetPopulationResponse{
+		Population:    make([]*pb.Person, len(indices)),
+		NextPageToken: nextPageToken,
+	}
+	for i, index := range indices {
+		person := ds.pbResponse.Population[index]
+		if q.fields != nil {
+			person = projectPerson(person, q.fields)
+		}
+		resp.Population[i] = person
+	}
+	return resp
+}
+
+// streamPeople returns the number of people matching q, a function giving
+// each of them in the form the JSON codecs encode, and the token of the next
+// page, for streaming people one at a time.
+func (q populationQuery) streamPeople(ds *dataset) (int, func(i int) any, string) {
+	var population []entity.Person
+	nextPageToken := ""
+	if q.isEmpty() {
+		population = ds.jsonResponse.Population
+	} else {
+		switch resp := q.jsonResponse(ds).(type) {
+		case *projectedJSONResponse:
+			return len(resp.Population), func(i int) any { return resp.Population[i] }, resp.NextPageToken
+		case *entity.GetPopulationResponse:
+			population, nextPageToken = resp.Population, resp.NextPageToken
+		}
+	}
+	return len(population), func(i int) any { return &population[i] }, nextPageToken
+}
+
+// Page tokens are opaque to clients, they encode the offset into the
+// filtered population.
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page_token")
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid page_token")
+	}
+	return offset, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	ds := newTestDataset(
+		testPerson("1", "admin", "CA", "Los Angeles", true),
+		testPerson("2", "user", "CA", "San Francisco", false),
+		testPerson("3", "user", "NY", "New York", true),
+		testPerson("4", "admin", "CA", "San Francisco", true),
+		testPerson("5", "user", "CA", "Los Angeles", true),
+	)
+	active, inactive := true, false
+
+	tests := []struct {
+		name          string
+		query         populationQuery
+		want          []int
+		wantNextToken string
+	}{
+		{name: "no filter", query: populationQuery{}, want: []int{0, 1, 2, 3, 4}},
+		{name: "role", query: populationQuery{role: "admin"}, want: []int{0, 3}},
+		{name: "active", query: populationQuery{active: &active}, want: []int{0, 2, 3, 4}},
+		{name: "inactive", query: populationQuery{active: &inactive}, want: []int{1}},
+		{name: "state and city", query: populationQuery{state: "CA", city: "San Francisco"}, want: []int{1, 3}},
+		{name: "no match", query: populationQuery{state: "TX"}, want: []int{}},
+		{name: "first page", query: populationQuery{pageSize: 2}, want: []int{0, 1}, wantNextToken: encodePageToken(2)},
+		{name: "middle page", query: populationQuery{pageSize: 2, offset: 2}, want: []int{2, 3}, wantNextToken: encodePageToken(4)},
+		{name: "last partial page", query: populationQuery{pageSize: 2, offset: 4}, want: []int{4}},
+		{name: "page ending on the last match", query: populationQuery{state: "CA", pageSize: 2, offset: 2}, want: []int{3, 4}},
+		{name: "filtered page", query: populationQuery{state: "CA", pageSize: 3}, want: []int{0, 1, 3}, wantNextToken: encodePageToken(3)},
+		{name: "offset past the end", query: populationQuery{offset: 10}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, nextToken := tt.query.apply(ds)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply() indices = %v, want %v", got, tt.want)
+			}
+			if nextToken != tt.wantNextToken {
+				t.Errorf("apply() next token = %q, want %q", nextToken, tt.wantNextToken)
+			}
+		})
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		token   string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", token: "", want: 0},
+		{name: "valid", token: encodePageToken(42), want: 42},
+		{name: "zero", token: encodePageToken(0), want: 0},
+		{name: "not base64", token: "!!!", wantErr: true},
+		{name: "padded base64", token: base64.URLEncoding.EncodeToString([]byte("4")), wantErr: true},
+		{name: "tampered", token: encode("4x"), wantErr: true},
+		{name: "negative", token: encode("-1"), wantErr: true},
+		{name: "out of range", token: encode("99999999999999999999"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePageToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodePageToken(%q) error = %v, want error %v", tt.token, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("decodePageToken(%q) = %d, want %d", tt.token, got, tt.want)
+			}
+		})
+	}
+}
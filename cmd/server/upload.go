@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/grpc"
+)
+
+// REST upload handler. POST and PUT accept an entity.GetPopulationResponse,
+// or newline-delimited people when sent as application/x-ndjson, which is
+// decoded line by line like UploadPopulation decodes messages. Bodies are
+// decoded with the configured JSON implementation.
+func handleUploadPopulation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c, _ := codec.Lookup(codec.JSON)
+	var received int
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-ndjson") {
+		reader := bufio.NewReader(r.Body)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			if len(bytes.TrimSpace(line)) > 0 {
+				var person entity.Person
+				if err := decodeTimed(c, line, &person); err != nil {
+					http.Error(w, "Invalid request body", http.StatusBadRequest)
+					return
+				}
+				received++
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		var population entity.GetPopulationResponse
+		if err := decodeTimed(c, body, &population); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		received = len(population.Population)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entity.CreatePopulationResponse{Received: received})
+}
+
+func (s *grpcServer) CreatePopulation(ctx context.Context, req *pb.CreatePopulationRequest) (*pb.CreatePopulationResponse, error) {
+	return &pb.CreatePopulationResponse{Received: int32(len(req.Population))}, nil
+}
+
+func (s *grpcServer) UploadPopulation(stream grpc.ClientStreamingServer[pb.CreatePopulationRequest, pb.CreatePopulationResponse]) error {
+	var received int32
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.CreatePopulationResponse{Received: received})
+		}
+		if err != nil {
+			return err
+		}
+		received += int32(len(batch.Population))
+	}
+}
+
+func (s *grpcServer) SyncPopulation(stream grpc.BidiStreamingServer[pb.CreatePopulationRequest, pb.CreatePopulationResponse]) error {
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.CreatePopulationResponse{Received: int32(len(batch.Population))}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package codec
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Decode maps into map[string]interface{} like the other codecs, rather than
+// CBOR's default of map[interface{}]interface{}. CBOR falls back to json
+// tags for struct keys.
+var cborDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+}.DecMode()
+
+func init() {
+	Register(Codec{
+		Name:        CBOR,
+		ContentType: "application/cbor",
+		Extension:   "cbor",
+		Encode: func(w io.Writer, v any) error {
+			return cbor.NewEncoder(w).Encode(v)
+		},
+		Decode: cborDecMode.Unmarshal,
+	})
+}
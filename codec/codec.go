@@ -0,0 +1,188 @@
+// Package codec is the registry of content types the REST server can
+// negotiate through the Accept header, shared with the client drivers that
+// request them.
+package codec
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	JSON     = "json"
+	Protobuf = "protobuf"
+	MsgPack  = "msgpack"
+	CBOR     = "cbor"
+	Gob      = "gob"
+)
+
+// Codec encodes population responses for one content type. Proto codecs
+// work on the generated protobuf messages, all others on the entity types.
+// Extension names the codec's fixture files.
+type Codec struct {
+	Name        string
+	ContentType string
+	Extension   string
+	Proto       bool
+	Encode      func(w io.Writer, v any) error
+	Decode      func(data []byte, v any) error
+}
+
+var (
+	codecs        = map[string]Codec{}
+	byContentType = map[string]Codec{}
+)
+
+// Register adds a codec, replacing any with the same name or content type.
+func Register(c Codec) {
+	codecs[c.Name] = c
+	byContentType[c.ContentType] = c
+}
+
+func init() {
+	UseJSON(JSONStdlib)
+	Register(Codec{
+		Name:        Protobuf,
+		ContentType: "application/x-protobuf",
+		Extension:   "pb",
+		Proto:       true,
+		Encode: func(w io.Writer, v any) error {
+			message, ok := v.(proto.Message)
+			if !ok {
+				return fmt.Errorf("protobuf codec cannot encode %T", v)
+			}
+			data, err := proto.Marshal(message)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		},
+		Decode: func(data []byte, v any) error {
+			message, ok := v.(proto.Message)
+			if !ok {
+				return fmt.Errorf("protobuf codec cannot decode into %T", v)
+			}
+			return proto.Unmarshal(data, message)
+		},
+	})
+}
+
+// Names returns the registered codecs in a stable order.
+func Names() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the codec registered under name.
+func Lookup(name string) (Codec, bool) {
+	c, ok := codecs[name]
+	return c, ok
+}
+
+// Negotiate picks the registered codec with the highest quality value in an
+// Accept header, the first listed on a tie. A codec's quality comes from the
+// most specific range matching it, and q=0 rules it out. An empty header or
+// a wildcard selects JSON, or the other codecs by name if JSON is ruled out.
+func Negotiate(accept string) (Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return codecs[JSON], true
+	}
+
+	ranges := parseAccept(accept)
+	var (
+		best     Codec
+		bestQ    float64
+		accepted bool
+	)
+	for _, r := range ranges {
+		for _, c := range r.candidates() {
+			if q := quality(ranges, c.ContentType); q > bestQ {
+				best, bestQ, accepted = c, q, true
+			}
+		}
+	}
+	return best, accepted
+}
+
+// acceptRange is a media range of an Accept header with its quality value.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the ranges of an Accept header in order, skipping
+// any that are malformed.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// matches reports how specifically the range matches contentType, from 1
+// for */* to 3 for the type itself, or 0 if it doesn't.
+func (r acceptRange) matches(contentType string) int {
+	switch {
+	case r.mediaType == contentType:
+		return 3
+	case strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(r.mediaType, "*")):
+		return 2
+	case r.mediaType == "*/*":
+		return 1
+	}
+	return 0
+}
+
+// candidates returns the codecs the range matches, JSON first for
+// wildcards.
+func (r acceptRange) candidates() []Codec {
+	if c, ok := byContentType[r.mediaType]; ok {
+		return []Codec{c}
+	}
+
+	var matched []Codec
+	if r.matches(codecs[JSON].ContentType) > 0 {
+		matched = append(matched, codecs[JSON])
+	}
+	for _, name := range Names() {
+		if c := codecs[name]; name != JSON && r.matches(c.ContentType) > 0 {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// quality is the quality value of the most specific range matching
+// contentType, or 0 if none does.
+func quality(ranges []acceptRange, contentType string) float64 {
+	var q float64
+	var specificity int
+	for _, r := range ranges {
+		if s := r.matches(contentType); s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,38 @@
+package codec
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+		wantOK bool
+	}{
+		{name: "empty", accept: "", want: JSON, wantOK: true},
+		{name: "exact", accept: "application/x-protobuf", want: Protobuf, wantOK: true},
+		{name: "first listed on a tie", accept: "application/msgpack, application/cbor", want: MsgPack, wantOK: true},
+		{name: "unsupported skipped", accept: "text/html, application/cbor", want: CBOR, wantOK: true},
+		{name: "any", accept: "*/*", want: JSON, wantOK: true},
+		{name: "any application", accept: "application/*", want: JSON, wantOK: true},
+		{name: "higher quality listed later", accept: "application/json;q=0.1, application/x-protobuf", want: Protobuf, wantOK: true},
+		{name: "zero quality is not acceptable", accept: "application/x-protobuf;q=0, application/json", want: JSON, wantOK: true},
+		{name: "wildcard with json ruled out", accept: "application/json;q=0, */*", want: CBOR, wantOK: true},
+		{name: "specific type beats wildcard", accept: "*/*;q=0.5, application/msgpack", want: MsgPack, wantOK: true},
+		{name: "everything ruled out", accept: "application/x-protobuf;q=0", wantOK: false},
+		{name: "invalid quality skipped", accept: "application/x-protobuf;q=high, application/cbor", want: CBOR, wantOK: true},
+		{name: "unsupported only", accept: "text/html", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept)
+			if ok != tt.wantOK {
+				t.Fatalf("Negotiate(%q) ok = %v, want %v", tt.accept, ok, tt.wantOK)
+			}
+			if ok && got.Name != tt.want {
+				t.Errorf("Negotiate(%q) = %s, want %s", tt.accept, got.Name, tt.want)
+			}
+		})
+	}
+}
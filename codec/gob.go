@@ -0,0 +1,24 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+)
+
+func init() {
+	// Projected responses hold nested objects as maps inside interface values
+	gob.Register(map[string]interface{}{})
+
+	Register(Codec{
+		Name:        Gob,
+		ContentType: "application/x-gob",
+		Extension:   "gob",
+		Encode: func(w io.Writer, v any) error {
+			return gob.NewEncoder(w).Encode(v)
+		},
+		Decode: func(data []byte, v any) error {
+			return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+		},
+	})
+}
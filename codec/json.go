@@ -0,0 +1,88 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	gojson "github.com/goccy/go-json"
+	"github.com/mailru/easyjson"
+)
+
+// JSON implementations the JSON codec can use.
+const (
+	JSONStdlib   = "stdlib"
+	JSONGoccy    = "goccy"
+	JSONEasyJSON = "easyjson"
+)
+
+type jsonImplementation struct {
+	encode func(w io.Writer, v any) error
+	decode func(data []byte, v any) error
+}
+
+var jsonImplementations = map[string]jsonImplementation{
+	JSONStdlib: {
+		encode: func(w io.Writer, v any) error {
+			return json.NewEncoder(w).Encode(v)
+		},
+		decode: json.Unmarshal,
+	},
+	JSONGoccy: {
+		encode: func(w io.Writer, v any) error {
+			return gojson.NewEncoder(w).Encode(v)
+		},
+		decode: gojson.Unmarshal,
+	},
+	// The entity types have generated marshallers, see entity/person.go.
+	// Anything else, like projected responses, goes through the stdlib.
+	JSONEasyJSON: {
+		encode: func(w io.Writer, v any) error {
+			marshaler, ok := v.(easyjson.Marshaler)
+			if !ok {
+				return json.NewEncoder(w).Encode(v)
+			}
+			if _, err := easyjson.MarshalToWriter(marshaler, w); err != nil {
+				return err
+			}
+			// Terminate with a newline like json.Encoder does
+			_, err := w.Write([]byte{'\n'})
+			return err
+		},
+		decode: func(data []byte, v any) error {
+			if unmarshaler, ok := v.(easyjson.Unmarshaler); ok {
+				return easyjson.Unmarshal(data, unmarshaler)
+			}
+			return json.Unmarshal(data, v)
+		},
+	},
+}
+
+// JSONImplementations returns the JSON implementations in a stable order.
+func JSONImplementations() []string {
+	names := make([]string, 0, len(jsonImplementations))
+	for name := range jsonImplementations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseJSON registers the JSON codec with the named implementation. It should
+// be called at startup, before the codec is used.
+func UseJSON(name string) error {
+	impl, ok := jsonImplementations[name]
+	if !ok {
+		return fmt.Errorf("unsupported JSON implementation %q, available: %v", name, JSONImplementations())
+	}
+
+	Register(Codec{
+		Name:        JSON,
+		ContentType: "application/json",
+		Extension:   "json",
+		Encode:      impl.encode,
+		Decode:      impl.decode,
+	})
+	return nil
+}
@@ -0,0 +1,27 @@
+package codec
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	Register(Codec{
+		Name:        MsgPack,
+		ContentType: "application/msgpack",
+		Extension:   "msgpack",
+		Encode: func(w io.Writer, v any) error {
+			encoder := msgpack.NewEncoder(w)
+			// Key structs by their JSON names so they match projected maps
+			encoder.SetCustomStructTag("json")
+			return encoder.Encode(v)
+		},
+		Decode: func(data []byte, v any) error {
+			decoder := msgpack.NewDecoder(bytes.NewReader(data))
+			decoder.SetCustomStructTag("json")
+			return decoder.Decode(v)
+		},
+	})
+}
@@ -0,0 +1,295 @@
+// Package compression provides the compression algorithms shared by the REST
+// and gRPC servers and clients, and tracks how much time is spent in them.
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Gzip   = "gzip"
+	Zstd   = "zstd"
+	Snappy = "snappy"
+)
+
+type algorithm struct {
+	newWriter func(w io.Writer) (io.WriteCloser, func())
+	newReader func(r io.Reader) (io.Reader, func(), error)
+}
+
+var algorithms = map[string]algorithm{
+	Gzip:   {newWriter: newGzipWriter, newReader: newGzipReader},
+	Zstd:   {newWriter: newZstdWriter, newReader: newZstdReader},
+	Snappy: {newWriter: newSnappyWriter, newReader: newSnappyReader},
+}
+
+// Names returns the supported algorithms in a stable order.
+func Names() []string {
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Supported reports whether name is a known algorithm.
+func Supported(name string) bool {
+	_, ok := algorithms[name]
+	return ok
+}
+
+// NewWriter returns a writer compressing into w. Close must be called to
+// flush the compressed stream; it does not close w.
+func NewWriter(name string, w io.Writer) (io.WriteCloser, error) {
+	alg, ok := algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression %q", name)
+	}
+
+	sink := &timedIO{w: w}
+	t := &timedWriter{sink: sink}
+	t.time(func() error {
+		t.w, t.release = alg.newWriter(sink)
+		return nil
+	})
+	return t, nil
+}
+
+// NewReader returns a reader decompressing r. Its resources are released on
+// Close or once the stream has been read to the end.
+func NewReader(name string, r io.Reader) (io.ReadCloser, error) {
+	alg, ok := algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression %q", name)
+	}
+
+	source := &timedIO{r: r}
+	t := &timedReader{source: source}
+	err := t.time(func() (err error) {
+		t.r, t.release, err = alg.newReader(source)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+var (
+	compressNanos   atomic.Int64
+	decompressNanos atomic.Int64
+)
+
+// Stats is the cumulative time this process spent compressing and
+// decompressing. Take the difference of two snapshots to time a run.
+type Stats struct {
+	CompressTime   time.Duration
+	DecompressTime time.Duration
+}
+
+func ReadStats() Stats {
+	return Stats{
+		CompressTime:   time.Duration(compressNanos.Load()),
+		DecompressTime: time.Duration(decompressNanos.Load()),
+	}
+}
+
+func (s Stats) Sub(other Stats) Stats {
+	return Stats{
+		CompressTime:   s.CompressTime - other.CompressTime,
+		DecompressTime: s.DecompressTime - other.DecompressTime,
+	}
+}
+
+// timedIO times the reads from the source of a decompressor or the writes
+// to the sink of a compressor. That time is spent waiting on the network or
+// on whoever consumes the stream, so it is taken out of the codec's.
+type timedIO struct {
+	r       io.Reader
+	w       io.Writer
+	blocked time.Duration
+}
+
+func (t *timedIO) Read(p []byte) (int, error) {
+	start := time.Now()
+	defer func() { t.blocked += time.Since(start) }()
+	return t.r.Read(p)
+}
+
+func (t *timedIO) Write(p []byte) (int, error) {
+	start := time.Now()
+	defer func() { t.blocked += time.Since(start) }()
+	return t.w.Write(p)
+}
+
+// timed runs f and returns how long it took, less the time it spent
+// blocked on io.
+func (t *timedIO) timed(f func() error) (time.Duration, error) {
+	blocked := t.blocked
+	start := time.Now()
+	err := f()
+	return time.Since(start) - (t.blocked - blocked), err
+}
+
+type timedWriter struct {
+	w       io.WriteCloser
+	sink    *timedIO
+	release func()
+	closed  bool
+}
+
+func (t *timedWriter) time(f func() error) error {
+	elapsed, err := t.sink.timed(f)
+	compressNanos.Add(int64(elapsed))
+	return err
+}
+
+func (t *timedWriter) Write(p []byte) (n int, err error) {
+	err = t.time(func() error {
+		n, err = t.w.Write(p)
+		return err
+	})
+	return n, err
+}
+
+// Flush pushes buffered data to the underlying writer, for streaming
+// responses.
+func (t *timedWriter) Flush() error {
+	flusher, ok := t.w.(interface{ Flush() error })
+	if !ok {
+		return nil
+	}
+	return t.time(flusher.Flush)
+}
+
+func (t *timedWriter) Close() error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	err := t.time(t.w.Close)
+	t.release()
+	return err
+}
+
+type timedReader struct {
+	r       io.Reader
+	source  *timedIO
+	release func()
+	done    bool
+}
+
+func (t *timedReader) time(f func() error) error {
+	elapsed, err := t.source.timed(f)
+	decompressNanos.Add(int64(elapsed))
+	return err
+}
+
+func (t *timedReader) Read(p []byte) (n int, err error) {
+	if t.done {
+		return 0, io.EOF
+	}
+
+	err = t.time(func() error {
+		n, err = t.r.Read(p)
+		return err
+	})
+	if err == io.EOF {
+		t.Close()
+	}
+	return n, err
+}
+
+func (t *timedReader) Close() error {
+	if !t.done {
+		t.done = true
+		t.release()
+	}
+	return nil
+}
+
+var (
+	gzipWriters   sync.Pool
+	gzipReaders   sync.Pool
+	zstdWriters   sync.Pool
+	zstdReaders   sync.Pool
+	snappyWriters sync.Pool
+)
+
+func newGzipWriter(w io.Writer) (io.WriteCloser, func()) {
+	writer, ok := gzipWriters.Get().(*gzip.Writer)
+	if ok {
+		writer.Reset(w)
+	} else {
+		writer = gzip.NewWriter(w)
+	}
+	return writer, func() { gzipWriters.Put(writer) }
+}
+
+func newGzipReader(r io.Reader) (io.Reader, func(), error) {
+	reader, ok := gzipReaders.Get().(*gzip.Reader)
+	if ok {
+		if err := reader.Reset(r); err != nil {
+			gzipReaders.Put(reader)
+			return nil, nil, err
+		}
+	} else {
+		var err error
+		if reader, err = gzip.NewReader(r); err != nil {
+			return nil, nil, err
+		}
+	}
+	return reader, func() { gzipReaders.Put(reader) }, nil
+}
+
+func newZstdWriter(w io.Writer) (io.WriteCloser, func()) {
+	writer, ok := zstdWriters.Get().(*zstd.Encoder)
+	if ok {
+		writer.Reset(w)
+	} else {
+		// Options are valid, so NewWriter can't fail
+		writer, _ = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	}
+	return writer, func() { zstdWriters.Put(writer) }
+}
+
+func newZstdReader(r io.Reader) (io.Reader, func(), error) {
+	reader, ok := zstdReaders.Get().(*zstd.Decoder)
+	if ok {
+		if err := reader.Reset(r); err != nil {
+			zstdReaders.Put(reader)
+			return nil, nil, err
+		}
+	} else {
+		var err error
+		if reader, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return reader, func() { zstdReaders.Put(reader) }, nil
+}
+
+func newSnappyWriter(w io.Writer) (io.WriteCloser, func()) {
+	writer, ok := snappyWriters.Get().(*snappy.Writer)
+	if ok {
+		writer.Reset(w)
+	} else {
+		writer = snappy.NewBufferedWriter(w)
+	}
+	return writer, func() { snappyWriters.Put(writer) }
+}
+
+func newSnappyReader(r io.Reader) (io.Reader, func(), error) {
+	return snappy.NewReader(r), func() {}, nil
+}
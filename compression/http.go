@@ -0,0 +1,78 @@
+package compression
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Negotiate picks the first supported algorithm from an Accept-Encoding
+// header, ignoring quality values. It returns "" if none is supported.
+func Negotiate(acceptEncoding string) string {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, _, _ := strings.Cut(part, ";")
+		if name = strings.TrimSpace(name); Supported(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Middleware compresses responses according to Accept-Encoding and
+// decompresses request bodies sent with a Content-Encoding.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if encoding := r.Header.Get("Content-Encoding"); encoding != "" {
+			body, err := NewReader(encoding, r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+				return
+			}
+			defer body.Close()
+			r.Body = body
+			r.Header.Del("Content-Encoding")
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := Negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer, err := NewWriter(encoding, w)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer writer.Close()
+
+		w.Header().Set("Content-Encoding", encoding)
+		next.ServeHTTP(&compressedResponseWriter{ResponseWriter: w, writer: writer}, r)
+	})
+}
+
+type compressedResponseWriter struct {
+	http.ResponseWriter
+	writer io.WriteCloser
+}
+
+func (c *compressedResponseWriter) WriteHeader(status int) {
+	// The length of the uncompressed body no longer applies
+	c.Header().Del("Content-Length")
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressedResponseWriter) Write(p []byte) (int, error) {
+	c.Header().Del("Content-Length")
+	return c.writer.Write(p)
+}
+
+func (c *compressedResponseWriter) Flush() {
+	if flusher, ok := c.writer.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -1,7 +1,85 @@
 package entity
 
+import "time"
+
 type Config struct {
-	MockSize   int    `env:"MOCK_SIZE" default:"1000"`
-	OutputDir  string `env:"OUTPUT_DIR" default:"./output"`
-	OutputFile string `env:"OUTPUT_FILE" default:"benchmark.json"`
+	MockSize   int    `env:"MOCK_SIZE" envDefault:"1000"`
+	OutputDir  string `env:"OUTPUT_DIR" envDefault:"./output"`
+	OutputFile string `env:"OUTPUT_FILE" envDefault:"benchmark.json"`
+
+	// Request filters and page size sent with every population request.
+	// Empty filters and a zero page size fetch the whole population.
+	FilterRole   string `env:"FILTER_ROLE"`
+	FilterActive *bool  `env:"FILTER_ACTIVE"`
+	FilterState  string `env:"FILTER_STATE"`
+	FilterCity   string `env:"FILTER_CITY"`
+	PageSize     int    `env:"PAGE_SIZE" envDefault:"0"`
+	// Fields limits responses to these Person fields, e.g. "id,address.city".
+	Fields []string `env:"FIELDS" envSeparator:","`
+
+	// TLSMode serves and connects over TLS: "tls", "mtls" for mutual TLS or
+	// empty for plaintext. The server generates a throwaway CA and
+	// certificates into TLSDir at startup and the client reads them there.
+	TLSMode string `env:"TLS_MODE"`
+	TLSDir  string `env:"TLS_DIR" envDefault:"./tls"`
+
+	// RestHTTPVersion is the HTTP version the REST drivers speak: "1.1", or
+	// "2" for HTTP/2 over TLS when TLSMode is set and cleartext h2c otherwise.
+	RestHTTPVersion string `env:"REST_HTTP_VERSION" envDefault:"1.1"`
+
+	// JSONEncoder is the JSON implementation used for /benchmark on the
+	// server and for decoding it on the client: "stdlib", "goccy" or
+	// "easyjson".
+	JSONEncoder string `env:"JSON_ENCODER" envDefault:"stdlib"`
+
+	// ServerMetrics makes the client scrape the server's /metrics endpoint
+	// around every measured run and add the difference to the results.
+	ServerMetrics bool `env:"SERVER_METRICS" envDefault:"true"`
+
+	// ResourceSampleInterval is how often client and server sample their CPU
+	// and memory usage. The client fetches the server's samples covering
+	// every measured run.
+	ResourceSampleInterval time.Duration `env:"RESOURCE_SAMPLE_INTERVAL" envDefault:"100ms"`
+
+	// Profile captures CPU, heap, allocs and mutex profiles of client and
+	// server during every measured run, written to OutputDir.
+	Profile bool `env:"PROFILE" envDefault:"false"`
+
+	// Compression requested by the client: "gzip", "zstd", "snappy" or empty
+	// for none. Applies to both REST and gRPC, in both directions.
+	Compression string `env:"COMPRESSION"`
+
+	// StreamBatchSize is the number of people per message for streaming
+	// protocols, 1 sends people individually.
+	StreamBatchSize int `env:"STREAM_BATCH_SIZE" envDefault:"1"`
+
+	// ScenarioFile points to a JSON entity.Scenario. When set the client runs
+	// the whole scenario matrix instead of a single run.
+	ScenarioFile string `env:"SCENARIO_FILE"`
+
+	// Protocols lists the client drivers to benchmark, in order.
+	Protocols []string `env:"PROTOCOLS" envSeparator:"," envDefault:"rest,rest-raw,grpc,grpc-raw"`
+
+	// Latency histogram settings. Significant figures controls the HDR
+	// precision (1-5), max latency is the highest value that can be tracked.
+	HistogramSignificantFigures int           `env:"HISTOGRAM_SIGNIFICANT_FIGURES" envDefault:"3"`
+	HistogramMaxLatency         time.Duration `env:"HISTOGRAM_MAX_LATENCY" envDefault:"1m"`
+
+	// Run size settings. A run stops after TotalRequests requests or once
+	// Duration has elapsed, whichever comes first; zero disables either limit.
+	// With neither set the client runs a default number of requests.
+	Concurrency    int           `env:"CONCURRENCY" envDefault:"100"`
+	TotalRequests  int           `env:"TOTAL_REQUESTS" envDefault:"0"`
+	Duration       time.Duration `env:"DURATION" envDefault:"0s"`
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" envDefault:"10s"`
+
+	// Warmup drives the same load before measuring, for WarmupRequests
+	// requests or WarmupDuration, and is reported separately.
+	WarmupRequests int           `env:"WARMUP_REQUESTS" envDefault:"0"`
+	WarmupDuration time.Duration `env:"WARMUP_DURATION" envDefault:"0s"`
+
+	// Load generation settings. "closed" runs back-to-back requests per
+	// client, "open" schedules requests at TargetRPS regardless of completions.
+	LoadMode  string  `env:"LOAD_MODE" envDefault:"closed"`
+	TargetRPS float64 `env:"TARGET_RPS" envDefault:"1000"`
 }
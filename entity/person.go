@@ -1,7 +1,10 @@
 package entity
 
+//go:generate easyjson -all -no_std_marshalers person.go
+
 type GetPopulationResponse struct {
-	Population []Person `json:"population"`
+	Population    []Person `json:"population"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
 }
 
 type Person struct {
@@ -27,3 +30,7 @@ type Address struct {
 	Country    string `json:"country"`
 	PostalCode string `json:"postal_code"`
 }
+
+type CreatePopulationResponse struct {
+	Received int `json:"received"`
+}
@@ -0,0 +1,420 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package entity
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity(in *jlexer.Lexer, out *Person) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "first_name":
+			out.FirstName = string(in.String())
+		case "last_name":
+			out.LastName = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "date_of_birth":
+			out.DateOfBirth = string(in.String())
+		case "phone_number":
+			out.PhoneNumber = string(in.String())
+		case "address":
+			(out.Address).UnmarshalEasyJSON(in)
+		case "created_at":
+			out.CreatedAt = string(in.String())
+		case "updated_at":
+			out.UpdatedAt = string(in.String())
+		case "active":
+			out.Active = bool(in.Bool())
+		case "role":
+			out.Role = string(in.String())
+		case "profile_image":
+			out.ProfileImage = string(in.String())
+		case "preferences":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Preferences = make(map[string]interface{})
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 interface{}
+					if m, ok := v1.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v1.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v1 = in.Interface()
+					}
+					(out.Preferences)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity(out *jwriter.Writer, in Person) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"first_name\":"
+		out.RawString(prefix)
+		out.String(string(in.FirstName))
+	}
+	{
+		const prefix string = ",\"last_name\":"
+		out.RawString(prefix)
+		out.String(string(in.LastName))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"date_of_birth\":"
+		out.RawString(prefix)
+		out.String(string(in.DateOfBirth))
+	}
+	{
+		const prefix string = ",\"phone_number\":"
+		out.RawString(prefix)
+		out.String(string(in.PhoneNumber))
+	}
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix)
+		(in.Address).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.String(string(in.CreatedAt))
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.String(string(in.UpdatedAt))
+	}
+	{
+		const prefix string = ",\"active\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Active))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	{
+		const prefix string = ",\"profile_image\":"
+		out.RawString(prefix)
+		out.String(string(in.ProfileImage))
+	}
+	{
+		const prefix string = ",\"preferences\":"
+		out.RawString(prefix)
+		if in.Preferences == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v2First := true
+			for v2Name, v2Value := range in.Preferences {
+				if v2First {
+					v2First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v2Name))
+				out.RawByte(':')
+				if m, ok := v2Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v2Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v2Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Person) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Person) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity(l, v)
+}
+func easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity1(in *jlexer.Lexer, out *GetPopulationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "population":
+			if in.IsNull() {
+				in.Skip()
+				out.Population = nil
+			} else {
+				in.Delim('[')
+				if out.Population == nil {
+					if !in.IsDelim(']') {
+						out.Population = make([]Person, 0, 0)
+					} else {
+						out.Population = []Person{}
+					}
+				} else {
+					out.Population = (out.Population)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 Person
+					(v3).UnmarshalEasyJSON(in)
+					out.Population = append(out.Population, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next_page_token":
+			out.NextPageToken = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity1(out *jwriter.Writer, in GetPopulationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"population\":"
+		out.RawString(prefix[1:])
+		if in.Population == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v4, v5 := range in.Population {
+				if v4 > 0 {
+					out.RawByte(',')
+				}
+				(v5).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NextPageToken != "" {
+		const prefix string = ",\"next_page_token\":"
+		out.RawString(prefix)
+		out.String(string(in.NextPageToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetPopulationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity1(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetPopulationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity1(l, v)
+}
+func easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity2(in *jlexer.Lexer, out *CreatePopulationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "received":
+			out.Received = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity2(out *jwriter.Writer, in CreatePopulationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"received\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Received))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CreatePopulationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity2(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CreatePopulationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity2(l, v)
+}
+func easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity3(in *jlexer.Lexer, out *Address) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "street":
+			out.Street = string(in.String())
+		case "city":
+			out.City = string(in.String())
+		case "state":
+			out.State = string(in.String())
+		case "country":
+			out.Country = string(in.String())
+		case "postal_code":
+			out.PostalCode = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity3(out *jwriter.Writer, in Address) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"street\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Street))
+	}
+	{
+		const prefix string = ",\"city\":"
+		out.RawString(prefix)
+		out.String(string(in.City))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"country\":"
+		out.RawString(prefix)
+		out.String(string(in.Country))
+	}
+	{
+		const prefix string = ",\"postal_code\":"
+		out.RawString(prefix)
+		out.String(string(in.PostalCode))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Address) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonDb0593a3EncodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity3(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Address) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonDb0593a3DecodeGithubComDimitriirfanBenchmarkGrpcVsRestServerEntity3(l, v)
+}
@@ -0,0 +1,134 @@
+package entity_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/entity"
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/testutil"
+)
+
+// Tests run in the package directory, the fixtures are relative to the root
+const root = ".."
+
+// populationCodec is a codec decoding into entity.GetPopulationResponse,
+// with the JSON implementation to select first for JSON.
+type populationCodec struct {
+	name  string
+	codec codec.Codec
+	json  string
+}
+
+// populationCodecs returns every codec the entity types are encoded with:
+// JSON once per implementation, then the other non-protobuf codecs.
+func populationCodecs(b *testing.B) []populationCodec {
+	b.Helper()
+
+	var codecs []populationCodec
+	jsonCodec, _ := codec.Lookup(codec.JSON)
+	for _, implementation := range codec.JSONImplementations() {
+		codecs = append(codecs, populationCodec{name: "json-" + implementation, codec: jsonCodec, json: implementation})
+	}
+	for _, name := range codec.Names() {
+		c, _ := codec.Lookup(name)
+		if name == codec.JSON || c.Proto {
+			continue
+		}
+		codecs = append(codecs, populationCodec{name: name, codec: c})
+	}
+
+	// Leave the default implementation selected for whatever runs next
+	b.Cleanup(func() { codec.UseJSON(codec.JSONStdlib) })
+	return codecs
+}
+
+// use selects the JSON implementation, if any, and returns the codec to
+// encode and decode with. UseJSON registers a new JSON codec, so it has to
+// be looked up again.
+func (p populationCodec) use(b *testing.B) codec.Codec {
+	b.Helper()
+	if p.json == "" {
+		return p.codec
+	}
+	if err := codec.UseJSON(p.json); err != nil {
+		b.Fatal(err)
+	}
+	c, _ := codec.Lookup(codec.JSON)
+	return c
+}
+
+func fixtureSizes(b *testing.B) []int {
+	b.Helper()
+	sizes, err := testutil.FixtureSizes(root)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return sizes
+}
+
+func readFixture(b *testing.B, size int, c codec.Codec) []byte {
+	b.Helper()
+	data, err := os.ReadFile(filepath.Join(root, testutil.FixturePath(size, c.Extension)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkPopulationMarshal(b *testing.B) {
+	codecs := populationCodecs(b)
+	stdlib := populationCodec{json: codec.JSONStdlib}
+
+	for _, size := range fixtureSizes(b) {
+		jsonCodec := stdlib.use(b)
+		var population entity.GetPopulationResponse
+		if err := jsonCodec.Decode(readFixture(b, size, jsonCodec), &population); err != nil {
+			b.Fatal(err)
+		}
+
+		for _, c := range codecs {
+			b.Run(fmt.Sprintf("size=%d/codec=%s", size, c.name), func(b *testing.B) {
+				encoder := c.use(b)
+				b.ReportAllocs()
+
+				var buf bytes.Buffer
+				for i := 0; i < b.N; i++ {
+					buf.Reset()
+					if err := encoder.Encode(&buf, &population); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.SetBytes(int64(buf.Len()))
+				b.ReportMetric(float64(buf.Len()), "bytes/op")
+			})
+		}
+	}
+}
+
+func BenchmarkPopulationUnmarshal(b *testing.B) {
+	codecs := populationCodecs(b)
+
+	for _, size := range fixtureSizes(b) {
+		for _, c := range codecs {
+			data := readFixture(b, size, c.codec)
+
+			b.Run(fmt.Sprintf("size=%d/codec=%s", size, c.name), func(b *testing.B) {
+				decoder := c.use(b)
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+
+				for i := 0; i < b.N; i++ {
+					var population entity.GetPopulationResponse
+					if err := decoder.Decode(data, &population); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(data)), "bytes/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Scenario describes a matrix of benchmark runs. Every combination of mock
+// size, concurrency level, field set and repetition runs all protocols. Empty
+// lists fall back to the values from Config.
+type Scenario struct {
+	Name        string   `json:"name"`
+	MockSizes   []int    `json:"mock_sizes"`
+	Protocols   []string `json:"protocols"`
+	Concurrency []int    `json:"concurrency"`
+	Repetitions int      `json:"repetitions"`
+
+	// FieldSets lists the Person fields requested in each run, an empty set
+	// requests full responses.
+	FieldSets [][]string `json:"field_sets"`
+
+	// ServerCommand, when set, is started with MOCK_SIZE for every mock size
+	// and stopped once that size is done, e.g. ["go", "run", "./cmd/server"].
+	// Otherwise the running server is switched through its admin API.
+	ServerCommand []string `json:"server_command"`
+}
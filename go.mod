@@ -5,14 +5,31 @@ go 1.22
 toolchain go1.22.11
 
 require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/goccy/go-json v0.10.5
+	github.com/golang/snappy v1.0.0
+	github.com/google/pprof v0.0.0-20241029153458-d1b30febd7db
+	github.com/klauspost/compress v1.18.0
+	github.com/mailru/easyjson v0.7.7
+	github.com/prometheus/client_golang v1.20.5
+	github.com/prometheus/client_model v0.6.1
+	github.com/prometheus/common v0.55.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 	google.golang.org/grpc v1.70.0
 	google.golang.org/protobuf v1.36.4
 )
 
 require (
+	github.com/HdrHistogram/hdrhistogram-go v1.1.2
+	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/caarlos0/env/v11 v11.3.1
-	github.com/golang/protobuf v1.5.4 // indirect
-	golang.org/x/net v0.34.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.34.0
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250127172529-29210b9bc287 // indirect
@@ -0,0 +1,130 @@
+// Package profiling captures pprof profiles of the current process over a
+// window of time, so the profiles of a benchmark run only cover that run.
+package profiling
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+
+	"github.com/google/pprof/profile"
+)
+
+// Profiles captured by a session.
+const (
+	CPU    = "cpu"
+	Heap   = "heap"
+	Allocs = "allocs"
+	Mutex  = "mutex"
+)
+
+// mutexProfileFraction samples one in this many mutex contention events
+// while a session is running.
+const mutexProfileFraction = 5
+
+// ErrRunning is returned when a session is started while another one is.
+var ErrRunning = errors.New("a profiling session is already running")
+
+var (
+	mu      sync.Mutex
+	current *session
+)
+
+// session is a running capture. Only one can run at a time per process, as
+// the runtime only allows a single CPU profile.
+type session struct {
+	cpu                   bytes.Buffer
+	allocsBase, mutexBase *profile.Profile
+	mutexFraction         int
+}
+
+// Start begins a session. The CPU profile covers the session, allocs and
+// mutex are reported as the difference between its start and end.
+func Start() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil {
+		return ErrRunning
+	}
+
+	s := &session{mutexFraction: runtime.SetMutexProfileFraction(mutexProfileFraction)}
+	var err error
+	if s.allocsBase, err = lookup(Allocs); err == nil {
+		s.mutexBase, err = lookup(Mutex)
+	}
+	if err == nil {
+		err = pprof.StartCPUProfile(&s.cpu)
+	}
+	if err != nil {
+		runtime.SetMutexProfileFraction(s.mutexFraction)
+		return err
+	}
+
+	current = s
+	return nil
+}
+
+// Stop ends the running session and returns its encoded profiles by name.
+func Stop() (map[string][]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := current
+	if s == nil {
+		return nil, errors.New("no profiling session is running")
+	}
+	current = nil
+
+	pprof.StopCPUProfile()
+	defer runtime.SetMutexProfileFraction(s.mutexFraction)
+
+	profiles := map[string][]byte{CPU: s.cpu.Bytes()}
+
+	var heap bytes.Buffer
+	if err := pprof.Lookup(Heap).WriteTo(&heap, 0); err != nil {
+		return nil, err
+	}
+	profiles[Heap] = heap.Bytes()
+
+	for name, base := range map[string]*profile.Profile{Allocs: s.allocsBase, Mutex: s.mutexBase} {
+		data, err := delta(name, base)
+		if err != nil {
+			return nil, fmt.Errorf("%s profile: %w", name, err)
+		}
+		profiles[name] = data
+	}
+	return profiles, nil
+}
+
+func lookup(name string) (*profile.Profile, error) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return profile.Parse(&buf)
+}
+
+// delta subtracts base from the current profile, the way net/http/pprof does
+// for its seconds parameter.
+func delta(name string, base *profile.Profile) ([]byte, error) {
+	p, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scale(-1)
+	merged, err := profile.Merge([]*profile.Profile{base, p})
+	if err != nil {
+		return nil, err
+	}
+	merged.TimeNanos = p.TimeNanos
+	merged.DurationNanos = p.TimeNanos - base.TimeNanos
+
+	var buf bytes.Buffer
+	if err := merged.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
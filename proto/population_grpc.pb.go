@@ -21,6 +21,10 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	PopulationService_GetPopulation_FullMethodName    = "/population.PopulationService/GetPopulation"
 	PopulationService_GetPopulationRaw_FullMethodName = "/population.PopulationService/GetPopulationRaw"
+	PopulationService_StreamPopulation_FullMethodName = "/population.PopulationService/StreamPopulation"
+	PopulationService_CreatePopulation_FullMethodName = "/population.PopulationService/CreatePopulation"
+	PopulationService_UploadPopulation_FullMethodName = "/population.PopulationService/UploadPopulation"
+	PopulationService_SyncPopulation_FullMethodName   = "/population.PopulationService/SyncPopulation"
 )
 
 // PopulationServiceClient is the client API for PopulationService service.
@@ -32,6 +36,14 @@ type PopulationServiceClient interface {
 	// GetPopulation returns a list of all people
 	GetPopulation(ctx context.Context, in *GetPopulationRequest, opts ...grpc.CallOption) (*GetPopulationResponse, error)
 	GetPopulationRaw(ctx context.Context, in *GetPopulationRequest, opts ...grpc.CallOption) (*RawResponse, error)
+	// StreamPopulation sends the population in batches of batch_size people
+	StreamPopulation(ctx context.Context, in *StreamPopulationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetPopulationResponse], error)
+	// CreatePopulation uploads a whole population in one message
+	CreatePopulation(ctx context.Context, in *CreatePopulationRequest, opts ...grpc.CallOption) (*CreatePopulationResponse, error)
+	// UploadPopulation uploads a population in batches and acknowledges once
+	UploadPopulation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreatePopulationRequest, CreatePopulationResponse], error)
+	// SyncPopulation uploads a population in batches and acknowledges every batch
+	SyncPopulation(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreatePopulationRequest, CreatePopulationResponse], error)
 }
 
 type populationServiceClient struct {
@@ -62,6 +74,61 @@ func (c *populationServiceClient) GetPopulationRaw(ctx context.Context, in *GetP
 	return out, nil
 }
 
+func (c *populationServiceClient) StreamPopulation(ctx context.Context, in *StreamPopulationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetPopulationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PopulationService_ServiceDesc.Streams[0], PopulationService_StreamPopulation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPopulationRequest, GetPopulationResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_StreamPopulationClient = grpc.ServerStreamingClient[GetPopulationResponse]
+
+func (c *populationServiceClient) CreatePopulation(ctx context.Context, in *CreatePopulationRequest, opts ...grpc.CallOption) (*CreatePopulationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePopulationResponse)
+	err := c.cc.Invoke(ctx, PopulationService_CreatePopulation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *populationServiceClient) UploadPopulation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreatePopulationRequest, CreatePopulationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PopulationService_ServiceDesc.Streams[1], PopulationService_UploadPopulation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreatePopulationRequest, CreatePopulationResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_UploadPopulationClient = grpc.ClientStreamingClient[CreatePopulationRequest, CreatePopulationResponse]
+
+func (c *populationServiceClient) SyncPopulation(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreatePopulationRequest, CreatePopulationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PopulationService_ServiceDesc.Streams[2], PopulationService_SyncPopulation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreatePopulationRequest, CreatePopulationResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_SyncPopulationClient = grpc.BidiStreamingClient[CreatePopulationRequest, CreatePopulationResponse]
+
 // PopulationServiceServer is the server API for PopulationService service.
 // All implementations must embed UnimplementedPopulationServiceServer
 // for forward compatibility.
@@ -71,6 +138,14 @@ type PopulationServiceServer interface {
 	// GetPopulation returns a list of all people
 	GetPopulation(context.Context, *GetPopulationRequest) (*GetPopulationResponse, error)
 	GetPopulationRaw(context.Context, *GetPopulationRequest) (*RawResponse, error)
+	// StreamPopulation sends the population in batches of batch_size people
+	StreamPopulation(*StreamPopulationRequest, grpc.ServerStreamingServer[GetPopulationResponse]) error
+	// CreatePopulation uploads a whole population in one message
+	CreatePopulation(context.Context, *CreatePopulationRequest) (*CreatePopulationResponse, error)
+	// UploadPopulation uploads a population in batches and acknowledges once
+	UploadPopulation(grpc.ClientStreamingServer[CreatePopulationRequest, CreatePopulationResponse]) error
+	// SyncPopulation uploads a population in batches and acknowledges every batch
+	SyncPopulation(grpc.BidiStreamingServer[CreatePopulationRequest, CreatePopulationResponse]) error
 	mustEmbedUnimplementedPopulationServiceServer()
 }
 
@@ -87,6 +162,18 @@ func (UnimplementedPopulationServiceServer) GetPopulation(context.Context, *GetP
 func (UnimplementedPopulationServiceServer) GetPopulationRaw(context.Context, *GetPopulationRequest) (*RawResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPopulationRaw not implemented")
 }
+func (UnimplementedPopulationServiceServer) StreamPopulation(*StreamPopulationRequest, grpc.ServerStreamingServer[GetPopulationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPopulation not implemented")
+}
+func (UnimplementedPopulationServiceServer) CreatePopulation(context.Context, *CreatePopulationRequest) (*CreatePopulationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePopulation not implemented")
+}
+func (UnimplementedPopulationServiceServer) UploadPopulation(grpc.ClientStreamingServer[CreatePopulationRequest, CreatePopulationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadPopulation not implemented")
+}
+func (UnimplementedPopulationServiceServer) SyncPopulation(grpc.BidiStreamingServer[CreatePopulationRequest, CreatePopulationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SyncPopulation not implemented")
+}
 func (UnimplementedPopulationServiceServer) mustEmbedUnimplementedPopulationServiceServer() {}
 func (UnimplementedPopulationServiceServer) testEmbeddedByValue()                           {}
 
@@ -144,6 +231,49 @@ func _PopulationService_GetPopulationRaw_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PopulationService_StreamPopulation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPopulationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PopulationServiceServer).StreamPopulation(m, &grpc.GenericServerStream[StreamPopulationRequest, GetPopulationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_StreamPopulationServer = grpc.ServerStreamingServer[GetPopulationResponse]
+
+func _PopulationService_CreatePopulation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePopulationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PopulationServiceServer).CreatePopulation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PopulationService_CreatePopulation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PopulationServiceServer).CreatePopulation(ctx, req.(*CreatePopulationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PopulationService_UploadPopulation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PopulationServiceServer).UploadPopulation(&grpc.GenericServerStream[CreatePopulationRequest, CreatePopulationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_UploadPopulationServer = grpc.ClientStreamingServer[CreatePopulationRequest, CreatePopulationResponse]
+
+func _PopulationService_SyncPopulation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PopulationServiceServer).SyncPopulation(&grpc.GenericServerStream[CreatePopulationRequest, CreatePopulationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PopulationService_SyncPopulationServer = grpc.BidiStreamingServer[CreatePopulationRequest, CreatePopulationResponse]
+
 // PopulationService_ServiceDesc is the grpc.ServiceDesc for PopulationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -159,6 +289,135 @@ var PopulationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPopulationRaw",
 			Handler:    _PopulationService_GetPopulationRaw_Handler,
 		},
+		{
+			MethodName: "CreatePopulation",
+			Handler:    _PopulationService_CreatePopulation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPopulation",
+			Handler:       _PopulationService_StreamPopulation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadPopulation",
+			Handler:       _PopulationService_UploadPopulation_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SyncPopulation",
+			Handler:       _PopulationService_SyncPopulation_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/population.proto",
+}
+
+const (
+	AdminService_SetDataset_FullMethodName = "/population.AdminService/SetDataset"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin service for controlling the benchmark server at runtime
+type AdminServiceClient interface {
+	// SetDataset swaps the served population for one of a different size
+	SetDataset(ctx context.Context, in *SetDatasetRequest, opts ...grpc.CallOption) (*SetDatasetResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) SetDataset(ctx context.Context, in *SetDatasetRequest, opts ...grpc.CallOption) (*SetDatasetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDatasetResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetDataset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// Admin service for controlling the benchmark server at runtime
+type AdminServiceServer interface {
+	// SetDataset swaps the served population for one of a different size
+	SetDataset(context.Context, *SetDatasetRequest) (*SetDatasetResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) SetDataset(context.Context, *SetDatasetRequest) (*SetDatasetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDataset not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_SetDataset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDatasetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetDataset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetDataset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetDataset(ctx, req.(*SetDatasetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "population.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetDataset",
+			Handler:    _AdminService_SetDataset_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/population.proto",
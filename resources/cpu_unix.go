@@ -0,0 +1,17 @@
+//go:build unix
+
+package resources
+
+import (
+	"syscall"
+	"time"
+)
+
+// cpuTime is the user and system CPU time used by the process.
+func cpuTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
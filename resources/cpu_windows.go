@@ -0,0 +1,8 @@
+package resources
+
+import "time"
+
+// cpuTime is left out on Windows, which has no rusage.
+func cpuTime() time.Duration {
+	return 0
+}
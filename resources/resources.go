@@ -0,0 +1,293 @@
+// Package resources samples the CPU and memory usage of the current process,
+// so client and server cost can be reported next to latency.
+package resources
+
+import (
+	"math"
+	"os"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sample is a point-in-time reading of the process. The alloc, GC and CPU
+// figures are cumulative since the process started. CPU time is zero on
+// Windows and RSS is zero outside Linux.
+type Sample struct {
+	Time             time.Time     `json:"time"`
+	CPUTime          time.Duration `json:"cpu_time"`
+	RSSBytes         uint64        `json:"rss_bytes"`
+	Goroutines       uint64        `json:"goroutines"`
+	HeapBytes        uint64        `json:"heap_bytes"`
+	TotalMemoryBytes uint64        `json:"total_memory_bytes"`
+	AllocBytes       uint64        `json:"alloc_bytes"`
+	AllocObjects     uint64        `json:"alloc_objects"`
+	GCCycles         uint64        `json:"gc_cycles"`
+	GCPauseTotal     time.Duration `json:"gc_pause_total"`
+}
+
+const (
+	goroutinesMetric   = "/sched/goroutines:goroutines"
+	heapMetric         = "/memory/classes/heap/objects:bytes"
+	totalMemoryMetric  = "/memory/classes/total:bytes"
+	allocBytesMetric   = "/gc/heap/allocs:bytes"
+	allocObjectsMetric = "/gc/heap/allocs:objects"
+	gcCyclesMetric     = "/gc/cycles/total:gc-cycles"
+	gcPausesMetric     = "/sched/pauses/total/gc:seconds"
+)
+
+var metricNames = []string{
+	goroutinesMetric,
+	heapMetric,
+	totalMemoryMetric,
+	allocBytesMetric,
+	allocObjectsMetric,
+	gcCyclesMetric,
+	gcPausesMetric,
+}
+
+// Read takes a sample of the current process. Unlike runtime.ReadMemStats
+// it doesn't stop the world.
+func Read() Sample {
+	samples := make([]metrics.Sample, len(metricNames))
+	for i, name := range metricNames {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	sample := Sample{
+		Time:     time.Now(),
+		CPUTime:  cpuTime(),
+		RSSBytes: rss(),
+	}
+	for _, s := range samples {
+		switch s.Name {
+		case goroutinesMetric:
+			sample.Goroutines = uint64Value(s)
+		case heapMetric:
+			sample.HeapBytes = uint64Value(s)
+		case totalMemoryMetric:
+			sample.TotalMemoryBytes = uint64Value(s)
+		case allocBytesMetric:
+			sample.AllocBytes = uint64Value(s)
+		case allocObjectsMetric:
+			sample.AllocObjects = uint64Value(s)
+		case gcCyclesMetric:
+			sample.GCCycles = uint64Value(s)
+		case gcPausesMetric:
+			if s.Value.Kind() == metrics.KindFloat64Histogram {
+				sample.GCPauseTotal = histogramTotal(s.Value.Float64Histogram())
+			}
+		}
+	}
+	return sample
+}
+
+func uint64Value(s metrics.Sample) uint64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return s.Value.Uint64()
+}
+
+// histogramTotal estimates the sum of a duration histogram from the
+// midpoints of its buckets.
+func histogramTotal(h *metrics.Float64Histogram) time.Duration {
+	var total float64
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		low, high := h.Buckets[i], h.Buckets[i+1]
+		switch {
+		case math.IsInf(low, -1):
+			low = high
+		case math.IsInf(high, 1):
+			high = low
+		}
+		total += float64(count) * (low + high) / 2
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+// rss reads the resident set size from /proc, it is zero where that isn't
+// available.
+func rss() uint64 {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
+
+// Sampler reads samples at a fixed interval in the background.
+type Sampler struct {
+	stop chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	samples []Sample
+	limit   int
+}
+
+// StartSampler starts sampling every interval. With a limit above zero only
+// the most recent limit samples are kept.
+func StartSampler(interval time.Duration, limit int) *Sampler {
+	s := &Sampler{
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+		limit: limit,
+	}
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.add(Read())
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return s
+}
+
+func (s *Sampler) add(sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = append(s.samples, sample)
+	if s.limit > 0 && len(s.samples) > 2*s.limit {
+		// Trim in bulk rather than on every sample
+		s.samples = append(s.samples[:0], s.samples[len(s.samples)-s.limit:]...)
+	}
+}
+
+// Since returns the samples taken after t, preceded by the last one taken
+// at or before it, and followed by a fresh reading.
+func (s *Sampler) Since(t time.Time) []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := len(s.samples)
+	for start > 0 && s.samples[start-1].Time.After(t) {
+		start--
+	}
+	start = max(start-1, 0)
+
+	samples := append([]Sample(nil), s.samples[start:]...)
+	return append(samples, Read())
+}
+
+// Stop ends sampling, takes a final reading and returns every sample kept.
+func (s *Sampler) Stop() []Sample {
+	close(s.stop)
+	<-s.done
+
+	s.add(Read())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.samples
+}
+
+// HeapPeak tracks the highest live heap at a finer interval than a Sampler
+// can afford, so short-lived peaks, like a buffered response, aren't missed.
+type HeapPeak struct {
+	stop chan struct{}
+	done chan struct{}
+	peak uint64
+}
+
+// StartHeapPeak starts reading the live heap every interval.
+func StartHeapPeak(interval time.Duration) *HeapPeak {
+	h := &HeapPeak{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		sample := []metrics.Sample{{Name: heapMetric}}
+		for {
+			metrics.Read(sample)
+			h.peak = max(h.peak, uint64Value(sample[0]))
+
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return h
+}
+
+// Stop ends tracking and returns the highest heap seen.
+func (h *HeapPeak) Stop() uint64 {
+	close(h.stop)
+	<-h.done
+	return h.peak
+}
+
+// Usage summarizes a series of samples covering a number of requests.
+type Usage struct {
+	CPUTime                time.Duration `json:"cpu_time"`
+	CPUPerRequest          time.Duration `json:"cpu_per_request"`
+	AllocBytes             uint64        `json:"alloc_bytes"`
+	AllocBytesPerRequest   float64       `json:"alloc_bytes_per_request"`
+	AllocObjectsPerRequest float64       `json:"alloc_objects_per_request"`
+	GCCycles               uint64        `json:"gc_cycles"`
+	GCPauseTotal           time.Duration `json:"gc_pause_total"`
+	PeakRSSBytes           uint64        `json:"peak_rss_bytes"`
+	PeakHeapBytes          uint64        `json:"peak_heap_bytes"`
+	PeakGoroutines         uint64        `json:"peak_goroutines"`
+	Samples                []Sample      `json:"samples"`
+}
+
+// Summarize computes the usage between the first and last sample.
+func Summarize(samples []Sample, requests int64) *Usage {
+	usage := &Usage{Samples: samples}
+	if len(samples) == 0 {
+		return usage
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	usage.CPUTime = last.CPUTime - first.CPUTime
+	usage.AllocBytes = last.AllocBytes - first.AllocBytes
+	usage.GCCycles = last.GCCycles - first.GCCycles
+	usage.GCPauseTotal = last.GCPauseTotal - first.GCPauseTotal
+	if requests > 0 {
+		usage.CPUPerRequest = usage.CPUTime / time.Duration(requests)
+		usage.AllocBytesPerRequest = float64(usage.AllocBytes) / float64(requests)
+		usage.AllocObjectsPerRequest = float64(last.AllocObjects-first.AllocObjects) / float64(requests)
+	}
+
+	for _, sample := range samples {
+		usage.PeakRSSBytes = max(usage.PeakRSSBytes, sample.RSSBytes)
+		usage.PeakHeapBytes = max(usage.PeakHeapBytes, sample.HeapBytes)
+		usage.PeakGoroutines = max(usage.PeakGoroutines, sample.Goroutines)
+	}
+	return usage
+}
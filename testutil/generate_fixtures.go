@@ -1,13 +1,17 @@
 package testutil
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
+	"github.com/dimitriirfan/benchmark-grpc-vs-rest-server/codec"
 	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
 	"google.golang.org/protobuf/proto"
 )
@@ -196,7 +200,36 @@ func generatePerson(index int) (*JSONPerson, *pb.Person) {
 	return jsonPerson, pbPerson
 }
 
-func GenerateFixtures(sizes []int) {
+// FixturePath returns where the fixture of the given size and format (a
+// codec extension such as "json" or "pb") is stored, relative to the
+// repository root.
+func FixturePath(size int, ext string) string {
+	return fmt.Sprintf("testutil/fixtures/fixtures_population_%d.%s", size, ext)
+}
+
+// FixtureSizes returns the sizes that have a JSON fixture, in ascending
+// order. root is the path to the repository root.
+func FixtureSizes(root string) ([]int, error) {
+	paths, err := filepath.Glob(filepath.Join(root, "testutil/fixtures/fixtures_population_*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make([]int, 0, len(paths))
+	for _, path := range paths {
+		var size int
+		if _, err := fmt.Sscanf(filepath.Base(path), "fixtures_population_%d.json", &size); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	return sizes, nil
+}
+
+// GenerateFixtures writes a new random population of each size in every
+// format.
+func GenerateFixtures(sizes []int) error {
 	rand.Seed(time.Now().UnixNano())
 
 	for _, size := range sizes {
@@ -216,19 +249,23 @@ func GenerateFixtures(sizes []int) {
 		jsonOutput := JSONResponse{Population: jsonPopulation}
 		jsonData, err := json.MarshalIndent(jsonOutput, "", "  ")
 		if err != nil {
-			log.Fatalf("Error marshaling JSON: %v", err)
+			return fmt.Errorf("marshal JSON: %w", err)
 		}
-		if err := os.WriteFile(fmt.Sprintf("testutil/fixtures/fixtures_population_%d.json", size), jsonData, 0644); err != nil {
-			log.Fatalf("Error writing JSON file: %v", err)
+		if err := os.WriteFile(FixturePath(size, "json"), jsonData, 0644); err != nil {
+			return err
 		}
 
 		// Write protobuf binary output
 		pbData, err := proto.Marshal(pbPopulation)
 		if err != nil {
-			log.Fatalf("Error marshaling protobuf: %v", err)
+			return fmt.Errorf("marshal protobuf: %w", err)
 		}
-		if err := os.WriteFile(fmt.Sprintf("testutil/fixtures/fixtures_population_%d.pb", size), pbData, 0644); err != nil {
-			log.Fatalf("Error writing protobuf file: %v", err)
+		if err := os.WriteFile(FixturePath(size, "pb"), pbData, 0644); err != nil {
+			return err
+		}
+
+		if err := writeCodecFixtures(size, jsonOutput); err != nil {
+			return err
 		}
 
 		// Log sizes for comparison
@@ -237,4 +274,26 @@ func GenerateFixtures(sizes []int) {
 		log.Printf("JSON size: %d bytes", len(jsonData))
 		log.Printf("Protobuf size: %d bytes", len(pbData))
 	}
+	return nil
+}
+
+// writeCodecFixtures writes the population in every registered codec other
+// than JSON and protobuf, which have fixtures of their own.
+func writeCodecFixtures(size int, population JSONResponse) error {
+	for _, name := range codec.Names() {
+		c, _ := codec.Lookup(name)
+		if name == codec.JSON || c.Proto {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := c.Encode(&buf, population); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(FixturePath(size, c.Extension), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		log.Printf("%s size: %d bytes", name, buf.Len())
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/dimitriirfan/benchmark-grpc-vs-rest-server/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Tests run in the package directory, the fixtures are relative to the root
+const root = ".."
+
+// loadProtobufFixtures returns the fixture sizes and the protobuf fixture of
+// each.
+func loadProtobufFixtures(b *testing.B) ([]int, map[int][]byte) {
+	b.Helper()
+
+	sizes, err := FixtureSizes(root)
+	if err != nil {
+		b.Fatal(err)
+	}
+	fixtures := make(map[int][]byte, len(sizes))
+	for _, size := range sizes {
+		data, err := os.ReadFile(filepath.Join(root, FixturePath(size, "pb")))
+		if err != nil {
+			b.Fatal(err)
+		}
+		fixtures[size] = data
+	}
+	return sizes, fixtures
+}
+
+func BenchmarkProtobufMarshal(b *testing.B) {
+	sizes, fixtures := loadProtobufFixtures(b)
+
+	for _, size := range sizes {
+		population := &pb.GetPopulationResponse{}
+		if err := proto.Unmarshal(fixtures[size], population); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			var data []byte
+			for i := 0; i < b.N; i++ {
+				var err error
+				if data, err = proto.Marshal(population); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.SetBytes(int64(len(data)))
+			b.ReportMetric(float64(len(data)), "bytes/op")
+		})
+	}
+}
+
+func BenchmarkProtobufUnmarshal(b *testing.B) {
+	sizes, fixtures := loadProtobufFixtures(b)
+
+	for _, size := range sizes {
+		data := fixtures[size]
+
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				population := &pb.GetPopulationResponse{}
+				if err := proto.Unmarshal(data, population); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/op")
+		})
+	}
+}